@@ -0,0 +1,18 @@
+package compute
+
+// Move address to another instance, used for failover
+func MoveAddress(provider AddressProvider, address *Address, node *Node) error {
+
+	if address.NodeId == node.Id {
+		return nil
+	}
+
+	if address.NodeId != "" || address.EniId != "" {
+		if err := provider.DisassociateAddress(address); err != nil {
+			return err
+		}
+	}
+
+	return provider.AssociateAddress(address, node)
+
+}
@@ -0,0 +1,45 @@
+package compute
+
+import (
+	"errors"
+	"time"
+)
+
+// Check command finished on instance
+func (r *CommandResult) Finished() bool {
+
+	return r.Status != CommandStatusPENDING && r.Status != CommandStatusRUNNING
+
+}
+
+// Poll results until command finished on all instances or timeout
+func WaitCommand(provider ComputeProvider, invocation *CommandInvocation, interval, timeout time.Duration) ([]*CommandResult, error) {
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		results, err := provider.GetCommandResults(invocation)
+		if err != nil {
+			return nil, err
+		}
+
+		finished := len(results) >= len(invocation.NodeIds)
+		for _, result := range results {
+			if !result.Finished() {
+				finished = false
+				break
+			}
+		}
+
+		if finished {
+			return results, nil
+		}
+
+		if time.Now().After(deadline) {
+			return results, errors.New("wait command timeout")
+		}
+
+		time.Sleep(interval)
+	}
+
+}
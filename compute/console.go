@@ -0,0 +1,39 @@
+package compute
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Check console session expired
+func (s *ConsoleSession) Expired() bool {
+
+	return !s.ExpiredAt.IsZero() && time.Now().After(s.ExpiredAt)
+
+}
+
+// Build the page url which can be opened or embedded in browser, the VNC
+// password is left out to keep it from history and logs, show s.Password
+// to the user to enter in the page instead
+func (s *ConsoleSession) EmbedUrl() string {
+
+	if s.Type != ConsoleTypeVNC {
+		return s.Url
+	}
+
+	switch s.Provider {
+	case "tencent_cvm", "tencent_lighthouse":
+		return "https://img.qcloud.com/qcloud/app/active_vnc/index.html?InstanceVncUrl=" + url.QueryEscape(s.Url)
+	case "alibaba_ecs", "alibaba_swas":
+		isWindows, _ := s.Extra["IsWindows"].(bool)
+		query := url.Values{}
+		query.Set("vncUrl", s.Url)
+		query.Set("instanceId", s.NodeId)
+		query.Set("isWindows", strconv.FormatBool(isWindows))
+		return "https://g.alicdn.com/aliyun/ecs-console-vnc2/0.0.8/index.html?" + query.Encode()
+	}
+
+	return s.Url
+
+}
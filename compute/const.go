@@ -20,6 +20,50 @@ const (
 	NodeStateUPDATING      NodeState = "updating"
 )
 
+type ChargeType string
+
+const (
+	ChargeTypePREPAID  ChargeType = "prepaid"
+	ChargeTypePOSTPAID ChargeType = "postpaid"
+	ChargeTypeUNKNOWN  ChargeType = "unknown"
+)
+
+type InternetChargeType string
+
+const (
+	InternetChargeBYTRAFFIC   InternetChargeType = "traffic"
+	InternetChargeBYBANDWIDTH InternetChargeType = "bandwidth"
+	InternetChargeBYPACKAGE   InternetChargeType = "package"
+	InternetChargeUNKNOWN     InternetChargeType = "unknown"
+)
+
+type ConsoleType string
+
+const (
+	ConsoleTypeVNC    ConsoleType = "vnc"
+	ConsoleTypeWEB    ConsoleType = "web"
+	ConsoleTypeSERIAL ConsoleType = "serial"
+)
+
+type SpotStrategy string
+
+const (
+	SpotStrategyNONE  SpotStrategy = ""
+	SpotStrategyLIMIT SpotStrategy = "limit"
+	SpotStrategyAUTO  SpotStrategy = "auto"
+)
+
+type AddressState string
+
+const (
+	AddressStateAVAILABLE AddressState = "available"
+	AddressStateINUSE     AddressState = "inuse"
+	AddressStateBINDING   AddressState = "binding"
+	AddressStateUNBINDING AddressState = "unbinding"
+	AddressStatePENDING   AddressState = "pending"
+	AddressStateUNKNOWN   AddressState = "unknown"
+)
+
 type NodeImageState string
 
 const (
@@ -73,6 +117,36 @@ const (
 	X86_X64 Architecture = "x86_64"
 	Arm     Architecture = "arm"
 	Arm64   Architecture = "arm64"
+
+	UnknownArch Architecture = "unknown"
+)
+
+type CommandType string
+
+const (
+	CommandTypeSHELL      CommandType = "shell"
+	CommandTypePOWERSHELL CommandType = "powershell"
+	CommandTypeBAT        CommandType = "bat"
+)
+
+type CommandStatus string
+
+const (
+	CommandStatusPENDING   CommandStatus = "pending"
+	CommandStatusRUNNING   CommandStatus = "running"
+	CommandStatusSUCCESS   CommandStatus = "success"
+	CommandStatusFAILED    CommandStatus = "failed"
+	CommandStatusTIMEOUT   CommandStatus = "timeout"
+	CommandStatusCANCELLED CommandStatus = "cancelled"
+)
+
+type ResourceType string
+
+const (
+	ResourceNode     ResourceType = "node"
+	ResourceVolume   ResourceType = "volume"
+	ResourceSnapshot ResourceType = "snapshot"
+	ResourceImage    ResourceType = "image"
 )
 
 type ComputeError string
@@ -81,4 +155,12 @@ const (
 	DeploymentError          ComputeError = "DeploymentError"
 	KeyPairError             ComputeError = "KeyPairError"
 	KeyPairDoesNotExistError ComputeError = "KeyPairDoesNotExistError"
+	NotSupportedError        ComputeError = "NotSupportedError"
+	AddressNotFoundError     ComputeError = "AddressNotFoundError"
+	UserDataTooLargeError    ComputeError = "UserDataTooLargeError"
+	InvalidParameterError    ComputeError = "InvalidParameterError"
 )
+
+func (e ComputeError) Error() string {
+	return string(e)
+}
@@ -0,0 +1,268 @@
+package deploy
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rehiy/cloudgo/compute"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Create node, wait for it running and reachable over ssh, then run steps in order
+func DeployNode(provider compute.ComputeProvider, opts *Options, steps []Step) (*Deployment, error) {
+
+	if opts == nil || opts.Create == nil {
+		return nil, &DeploymentError{Err: errors.New("missing create options")}
+	}
+
+	config, err := clientConfig(opts)
+	if err != nil {
+		return nil, &DeploymentError{Err: err}
+	}
+
+	create := *opts.Create
+
+	// 通过自定义数据注入登录公钥
+	key := ""
+	if opts.InjectKey && len(opts.PrivateKey) > 0 {
+		signer, _ := ssh.ParsePrivateKey(opts.PrivateKey)
+		userData := compute.UserData{}
+		if create.UserData != nil {
+			userData = *create.UserData
+		}
+		key = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+		userData.SshKeys = append(append([]string{}, userData.SshKeys...), key)
+		create.UserData = &userData
+	}
+
+	node, err := provider.CreateNode(&create)
+
+	// 平台不支持自定义数据时，重置密码登录后再安装公钥
+	fallback := key != "" && opts.Create.UserData == nil && errors.Is(err, compute.NotSupportedError)
+	if fallback {
+		if opts.Password == "" {
+			return nil, &DeploymentError{Err: errors.New("user data not supported, password required to install key")}
+		}
+		create.UserData = nil
+		node, err = provider.CreateNode(&create)
+	}
+
+	if err != nil {
+		return nil, &DeploymentError{Err: err}
+	}
+	if node == nil {
+		return nil, &DeploymentError{Err: errors.New("node not created")}
+	}
+
+	node, err = WaitNode(provider, node, opts)
+	if err != nil {
+		return nil, &DeploymentError{Node: node, Err: err}
+	}
+
+	if fallback {
+		// root 为镜像默认用户，部分平台不支持指定用户名
+		username := opts.Username
+		if username == "root" {
+			username = ""
+		}
+		err = provider.ResetNodePassword(node, opts.Password, &compute.PasswordResetOpts{
+			Username:  username,
+			ForceStop: true,
+			Reboot:    true,
+		})
+		if err != nil {
+			return nil, &DeploymentError{Node: node, Err: err}
+		}
+		if node, err = WaitNode(provider, node, opts); err != nil {
+			return nil, &DeploymentError{Node: node, Err: err}
+		}
+		steps = append([]Step{&SshKeyStep{PublicKey: key}}, steps...)
+	}
+
+	host := opts.Host
+	if host == "" {
+		host = node.PublicIp
+	}
+
+	results, err := RunSteps(host, config, steps, opts)
+	if err != nil {
+		if de, ok := err.(*DeploymentError); ok {
+			de.Node = node
+		}
+		return nil, err
+	}
+
+	return &Deployment{Node: node, Results: results}, nil
+
+}
+
+// Wait until node is running and has public ip, stopped node is started once
+func WaitNode(provider compute.ComputeProvider, node *compute.Node, opts *Options) (*compute.Node, error) {
+
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	timeout, interval := waitDurations(opts)
+	deadline := time.Now().Add(timeout)
+
+	started := false
+
+	for {
+		detail, err := provider.DetailNode(node.Id)
+		if err == nil && detail != nil {
+			node = detail
+			if node.State == compute.NodeStateERROR {
+				return node, errors.New("node in error state")
+			}
+			// 部分平台创建后为停止状态，需手动启动
+			if node.State == compute.NodeStateSTOPPED && !started {
+				if err := provider.StartNode(node); err != nil {
+					return node, err
+				}
+				started = true
+			}
+			if node.State == compute.NodeStateRUNNING && (node.PublicIp != "" || opts.Host != "") {
+				return node, nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			return node, errors.New("wait node running timeout")
+		}
+
+		time.Sleep(interval)
+	}
+
+}
+
+// Connect to host over ssh with retry and run steps in order
+func RunSteps(host string, config *ssh.ClientConfig, steps []Step, opts *Options) ([]*StepResult, error) {
+
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	port := opts.Port
+	if port == 0 {
+		port = 22
+	}
+
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+
+	client, err := dial(addr, config, opts)
+	if err != nil {
+		return nil, &DeploymentError{Err: err}
+	}
+
+	defer client.Close()
+
+	results := []*StepResult{}
+
+	for _, step := range steps {
+		result, err := step.Run(client)
+		if result != nil {
+			result.Name = step.Name()
+			results = append(results, result)
+		}
+		if err != nil {
+			return results, &DeploymentError{Step: step.Name(), Results: results, Err: err}
+		}
+	}
+
+	return results, nil
+
+}
+
+// Build ssh client config from options
+func ClientConfig(opts *Options) (*ssh.ClientConfig, error) {
+
+	return clientConfig(opts)
+
+}
+
+// 生成 SSH 客户端配置
+func clientConfig(opts *Options) (*ssh.ClientConfig, error) {
+
+	username := opts.Username
+	if username == "" {
+		username = "root"
+	}
+
+	auth := []ssh.AuthMethod{}
+
+	if len(opts.PrivateKey) > 0 {
+		signer, err := ssh.ParsePrivateKey(opts.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+
+	if opts.Password != "" {
+		auth = append(auth, ssh.Password(opts.Password))
+	}
+
+	if len(auth) == 0 {
+		return nil, errors.New("missing ssh private key or password")
+	}
+
+	callback := opts.HostKeyCallback
+	if callback == nil {
+		if !opts.InsecureIgnoreHostKey {
+			return nil, errors.New("missing host key callback")
+		}
+		callback = ssh.InsecureIgnoreHostKey()
+	}
+
+	config := &ssh.ClientConfig{
+		User:            username,
+		Auth:            auth,
+		HostKeyCallback: callback,
+		Timeout:         10 * time.Second,
+	}
+
+	return config, nil
+
+}
+
+// 重试连接，直到服务可用或超时
+func dial(addr string, config *ssh.ClientConfig, opts *Options) (*ssh.Client, error) {
+
+	timeout, interval := waitDurations(opts)
+	deadline := time.Now().Add(timeout)
+
+	for {
+		client, err := ssh.Dial("tcp", addr, config)
+		if err == nil {
+			return client, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+
+		time.Sleep(interval)
+	}
+
+}
+
+// 等待时长默认值
+func waitDurations(opts *Options) (time.Duration, time.Duration) {
+
+	timeout, interval := 10*time.Minute, 5*time.Second
+
+	if opts != nil && opts.WaitTimeout > 0 {
+		timeout = opts.WaitTimeout
+	}
+	if opts != nil && opts.PollInterval > 0 {
+		interval = opts.PollInterval
+	}
+
+	return timeout, interval
+
+}
@@ -0,0 +1,471 @@
+package deploy
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rehiy/cloudgo/compute"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// 测试用 SSH 服务，在临时目录中通过 sh 执行命令
+type testServer struct {
+	addr   string
+	home   string
+	host   ssh.Signer
+	client ssh.Signer
+}
+
+func newTestServer(t *testing.T) *testServer {
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires /bin/sh")
+	}
+
+	server := &testServer{
+		home:   t.TempDir(),
+		host:   newSigner(t),
+		client: newSigner(t),
+	}
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) == string(server.client.PublicKey().Marshal()) {
+				return nil, nil
+			}
+			return nil, errors.New("unknown key")
+		},
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if string(password) == "secret" {
+				return nil, nil
+			}
+			return nil, errors.New("wrong password")
+		},
+	}
+	config.AddHostKey(server.host)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { listener.Close() })
+
+	server.addr = listener.Addr().String()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn, config)
+		}
+	}()
+
+	return server
+
+}
+
+func (s *testServer) serve(conn net.Conn, config *ssh.ServerConfig) {
+
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			newChannel.Reject(ssh.UnknownChannelType, "session only")
+			continue
+		}
+
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+
+		go s.session(channel, requests)
+	}
+
+}
+
+func (s *testServer) session(channel ssh.Channel, requests <-chan *ssh.Request) {
+
+	defer channel.Close()
+
+	for req := range requests {
+		if req.Type != "exec" {
+			req.Reply(false, nil)
+			continue
+		}
+
+		payload := struct{ Command string }{}
+		if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+			req.Reply(false, nil)
+			return
+		}
+
+		req.Reply(true, nil)
+
+		cmd := exec.Command("/bin/sh", "-c", payload.Command)
+		cmd.Dir = s.home
+		cmd.Env = []string{"HOME=" + s.home, "PATH=" + os.Getenv("PATH")}
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = channel, channel, channel.Stderr()
+
+		status := uint32(0)
+		if err := cmd.Run(); err != nil {
+			status = 255
+			var exit *exec.ExitError
+			if errors.As(err, &exit) {
+				status = uint32(exit.ExitCode())
+			}
+		}
+
+		channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+		return
+	}
+
+}
+
+func (s *testServer) options() *Options {
+
+	return &Options{
+		HostKeyCallback: ssh.FixedHostKey(s.host.PublicKey()),
+		WaitTimeout:     5 * time.Second,
+		PollInterval:    10 * time.Millisecond,
+	}
+
+}
+
+func (s *testServer) config() *ssh.ClientConfig {
+
+	return &ssh.ClientConfig{
+		User:            "root",
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(s.client)},
+		HostKeyCallback: ssh.FixedHostKey(s.host.PublicKey()),
+		Timeout:         5 * time.Second,
+	}
+
+}
+
+func newSigner(t *testing.T) ssh.Signer {
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signer
+
+}
+
+func splitHostPort(t *testing.T, addr string) (string, int) {
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := net.LookupPort("tcp", port)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return host, p
+
+}
+
+func TestRunSteps(t *testing.T) {
+
+	server := newTestServer(t)
+	host, port := splitHostPort(t, server.addr)
+
+	opts := server.options()
+	opts.Port = port
+
+	target := filepath.Join(server.home, "app.conf")
+	key := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(server.client.PublicKey())))
+
+	steps := []Step{
+		&FileStep{Content: []byte("listen 8080\n"), Target: target, Mode: 0600},
+		&ScriptStep{Script: "echo hello\necho world >&2"},
+		&SshKeyStep{PublicKey: key},
+		&SshKeyStep{PublicKey: key + "\n"},
+	}
+
+	results, err := RunSteps(host, server.config(), steps, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != len(steps) {
+		t.Fatalf("got %d results, want %d", len(results), len(steps))
+	}
+
+	for i, result := range results {
+		if result.Name != steps[i].Name() {
+			t.Errorf("result %d name = %q, want %q", i, result.Name, steps[i].Name())
+		}
+		if result.ExitStatus != 0 {
+			t.Errorf("result %d exit status = %d", i, result.ExitStatus)
+		}
+	}
+
+	content, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "listen 8080\n" {
+		t.Errorf("file content = %q", content)
+	}
+
+	info, err := os.Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %o, want 600", info.Mode().Perm())
+	}
+
+	if results[1].Stdout != "hello\n" || results[1].Stderr != "world\n" {
+		t.Errorf("script output = %q, %q", results[1].Stdout, results[1].Stderr)
+	}
+
+	keys, err := os.ReadFile(filepath.Join(server.home, ".ssh", "authorized_keys"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(keys), key) != 1 {
+		t.Errorf("authorized_keys = %q, want key once", keys)
+	}
+
+}
+
+func TestRunStepsFailure(t *testing.T) {
+
+	server := newTestServer(t)
+	host, port := splitHostPort(t, server.addr)
+
+	opts := server.options()
+	opts.Port = port
+
+	steps := []Step{
+		&ScriptStep{Script: "echo ok"},
+		&ScriptStep{Script: "echo broken >&2; exit 3"},
+		&ScriptStep{Script: "touch never"},
+	}
+
+	results, err := RunSteps(host, server.config(), steps, opts)
+
+	var de *DeploymentError
+	if !errors.As(err, &de) {
+		t.Fatalf("err = %v, want *DeploymentError", err)
+	}
+
+	if !errors.Is(err, compute.DeploymentError) {
+		t.Error("error does not match compute.DeploymentError")
+	}
+
+	if de.Step != steps[1].Name() {
+		t.Errorf("failed step = %q, want %q", de.Step, steps[1].Name())
+	}
+
+	if len(results) != 2 || len(de.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+
+	if results[1].ExitStatus != 3 || results[1].Stderr != "broken\n" {
+		t.Errorf("failed result = %+v", results[1])
+	}
+
+	if _, err := os.Stat(filepath.Join(server.home, "never")); !os.IsNotExist(err) {
+		t.Error("step after failure was run")
+	}
+
+}
+
+func TestRunStepsInvalidKey(t *testing.T) {
+
+	server := newTestServer(t)
+	host, port := splitHostPort(t, server.addr)
+
+	opts := server.options()
+	opts.Port = port
+
+	steps := []Step{&SshKeyStep{PublicKey: "not a key"}}
+
+	_, err := RunSteps(host, server.config(), steps, opts)
+
+	var de *DeploymentError
+	if !errors.As(err, &de) || de.Step != steps[0].Name() {
+		t.Fatalf("err = %v, want failure of %q", err, steps[0].Name())
+	}
+
+}
+
+func TestClientConfig(t *testing.T) {
+
+	signer := newSigner(t)
+
+	tests := []struct {
+		name    string
+		opts    *Options
+		wantErr bool
+	}{
+		{"password with callback", &Options{Password: "secret", HostKeyCallback: ssh.FixedHostKey(signer.PublicKey())}, false},
+		{"password insecure", &Options{Password: "secret", InsecureIgnoreHostKey: true}, false},
+		{"missing host key callback", &Options{Password: "secret"}, true},
+		{"missing auth", &Options{InsecureIgnoreHostKey: true}, true},
+		{"invalid private key", &Options{PrivateKey: []byte("invalid"), InsecureIgnoreHostKey: true}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ClientConfig(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && config.User != "root" {
+				t.Errorf("user = %q, want root", config.User)
+			}
+		})
+	}
+
+}
+
+// 模拟平台，仅实现部署所需的方法，不支持自定义数据
+type fakeProvider struct {
+	compute.ComputeProvider
+	node    *compute.Node
+	started int
+	reset   string
+}
+
+func (p *fakeProvider) CreateNode(opts *compute.NodeCreateOpts) (*compute.Node, error) {
+
+	if opts.UserData != nil {
+		return nil, compute.NotSupportedError
+	}
+
+	node := *p.node
+	return &node, nil
+
+}
+
+func (p *fakeProvider) ResetNodePassword(node *compute.Node, password string, opts *compute.PasswordResetOpts) error {
+
+	if opts.Username != "" {
+		return compute.NotSupportedError
+	}
+
+	p.reset = password
+	return nil
+
+}
+
+func (p *fakeProvider) DetailNode(id string) (*compute.Node, error) {
+
+	node := *p.node
+	return &node, nil
+
+}
+
+func (p *fakeProvider) StartNode(node *compute.Node) error {
+
+	p.started++
+	p.node.State = compute.NodeStateRUNNING
+	p.node.PublicIp = "203.0.113.10"
+	return nil
+
+}
+
+func TestWaitNodeStartsStopped(t *testing.T) {
+
+	provider := &fakeProvider{node: &compute.Node{Id: "i-1", State: compute.NodeStateSTOPPED}}
+
+	opts := &Options{WaitTimeout: time.Second, PollInterval: time.Millisecond}
+
+	node, err := WaitNode(provider, &compute.Node{Id: "i-1"}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if provider.started != 1 {
+		t.Errorf("started %d times, want 1", provider.started)
+	}
+
+	if node.State != compute.NodeStateRUNNING || node.PublicIp == "" {
+		t.Errorf("node = %+v, want running with public ip", node)
+	}
+
+}
+
+func TestDeployNodeInstallsKeyWithoutUserData(t *testing.T) {
+
+	server := newTestServer(t)
+	host, port := splitHostPort(t, server.addr)
+
+	provider := &fakeProvider{node: &compute.Node{Id: "i-1", State: compute.NodeStateRUNNING}}
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := server.options()
+	opts.Create = &compute.NodeCreateOpts{Name: "web-1"}
+	opts.Host = host
+	opts.Port = port
+	opts.Username = "root"
+	opts.Password = "secret"
+	opts.PrivateKey = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	opts.InjectKey = true
+
+	deployment, err := DeployNode(provider, opts, []Step{&ScriptStep{Script: "echo done"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if provider.reset != "secret" {
+		t.Errorf("password reset = %q, want secret", provider.reset)
+	}
+
+	if len(deployment.Results) != 2 || deployment.Results[0].Name != "install ssh key" {
+		t.Fatalf("results = %+v, want key step first", deployment.Results)
+	}
+
+	keys, err := os.ReadFile(filepath.Join(server.home, ".ssh", "authorized_keys"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(keys), "ssh-ed25519 ") {
+		t.Errorf("authorized_keys = %q", keys)
+	}
+
+}
@@ -0,0 +1,136 @@
+package deploy
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// upload content or local file to target path
+
+type FileStep struct {
+	Source  string // local file path, used when Content is nil
+	Content []byte
+	Target  string
+	Mode    os.FileMode // default 0644
+}
+
+func (s *FileStep) Name() string {
+	return "upload " + s.Target
+}
+
+func (s *FileStep) Run(client *ssh.Client) (*StepResult, error) {
+
+	content := s.Content
+	if content == nil {
+		data, err := os.ReadFile(s.Source)
+		if err != nil {
+			return nil, err
+		}
+		content = data
+	}
+
+	mode := s.Mode
+	if mode == 0 {
+		mode = 0644
+	}
+
+	target := shellQuote(s.Target)
+	command := "cat > " + target + " && chmod " + strconv.FormatUint(uint64(mode.Perm()), 8) + " " + target
+
+	return execute(client, command, bytes.NewReader(content))
+
+}
+
+// run script with interpreter, script is sent through stdin
+
+type ScriptStep struct {
+	Script      string
+	Interpreter string // default /bin/sh -s
+}
+
+func (s *ScriptStep) Name() string {
+	line := strings.SplitN(strings.TrimSpace(s.Script), "\n", 2)[0]
+	return "script " + line
+}
+
+func (s *ScriptStep) Run(client *ssh.Client) (*StepResult, error) {
+
+	interpreter := s.Interpreter
+	if interpreter == "" {
+		interpreter = "/bin/sh -s"
+	}
+
+	return execute(client, interpreter, strings.NewReader(s.Script))
+
+}
+
+// append public key to authorized_keys of login user
+
+type SshKeyStep struct {
+	PublicKey string
+}
+
+func (s *SshKeyStep) Name() string {
+	return "install ssh key"
+}
+
+func (s *SshKeyStep) Run(client *ssh.Client) (*StepResult, error) {
+
+	key := strings.TrimSpace(s.PublicKey)
+	if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key)); err != nil {
+		return nil, err
+	}
+
+	command := "mkdir -p ~/.ssh && chmod 700 ~/.ssh && touch ~/.ssh/authorized_keys" +
+		" && (grep -qxF " + shellQuote(key) + " ~/.ssh/authorized_keys || echo " + shellQuote(key) + " >> ~/.ssh/authorized_keys)" +
+		" && chmod 600 ~/.ssh/authorized_keys"
+
+	return execute(client, command, nil)
+
+}
+
+// 执行命令并收集输出
+func execute(client *ssh.Client, command string, stdin io.Reader) (*StepResult, error) {
+
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, err
+	}
+
+	defer session.Close()
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	session.Stdout, session.Stderr = stdout, stderr
+
+	if stdin != nil {
+		session.Stdin = stdin
+	}
+
+	err = session.Run(command)
+
+	result := &StepResult{
+		Stdout: stdout.String(),
+		Stderr: stderr.String(),
+	}
+
+	var exit *ssh.ExitError
+	if errors.As(err, &exit) {
+		result.ExitStatus = exit.ExitStatus()
+	}
+
+	return result, err
+
+}
+
+// 单引号转义
+func shellQuote(s string) string {
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+
+}
@@ -0,0 +1,75 @@
+package deploy
+
+import (
+	"time"
+
+	"github.com/rehiy/cloudgo/compute"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// deployment step run over ssh
+
+type Step interface {
+	// Name of step used in result and error
+	Name() string
+
+	// Run step on connected node
+	Run(client *ssh.Client) (*StepResult, error)
+}
+
+// options for deploying node
+
+type Options struct {
+	Create                *compute.NodeCreateOpts
+	Host                  string // override address, default node public ip
+	Port                  int    // default 22
+	Username              string // default root
+	PrivateKey            []byte // PEM encoded private key
+	Password              string
+	InjectKey             bool                // add public key of PrivateKey to Create.UserData, or install it after password login
+	HostKeyCallback       ssh.HostKeyCallback // required unless InsecureIgnoreHostKey
+	InsecureIgnoreHostKey bool                // accept any host key when HostKeyCallback is nil
+	WaitTimeout           time.Duration       // wait for running and ssh, default 10m
+	PollInterval          time.Duration       // default 5s
+}
+
+// output of step, ExitStatus is the remote command exit code
+
+type StepResult struct {
+	Name       string
+	Stdout     string
+	Stderr     string
+	ExitStatus int
+}
+
+// deployed node with step results
+
+type Deployment struct {
+	Node    *compute.Node
+	Results []*StepResult
+}
+
+// failed deployment, Node is set when created so callers can clean up
+
+type DeploymentError struct {
+	Node    *compute.Node
+	Step    string
+	Results []*StepResult
+	Err     error
+}
+
+func (e *DeploymentError) Error() string {
+	if e.Step != "" {
+		return string(compute.DeploymentError) + ": " + e.Step + ": " + e.Err.Error()
+	}
+	return string(compute.DeploymentError) + ": " + e.Err.Error()
+}
+
+func (e *DeploymentError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DeploymentError) Is(target error) bool {
+	return target == compute.DeploymentError
+}
@@ -11,7 +11,7 @@ func NewAbstractDriver() *AbstractDriver {
 }
 
 // List all instance
-func (p *AbstractDriver) ListNodes() ([]*compute.Node, error) {
+func (p *AbstractDriver) ListNodes(filter *compute.NodeFilter) ([]*compute.Node, error) {
 	return nil, nil
 }
 
@@ -25,6 +25,16 @@ func (p *AbstractDriver) CreateNode(opts *compute.NodeCreateOpts) (*compute.Node
 	return nil, nil
 }
 
+// Update name, description or hostname of instance
+func (p *AbstractDriver) UpdateNode(node *compute.Node, attrs *compute.NodeUpdateAttrs) error {
+	return nil
+}
+
+// Reset login password of instance
+func (p *AbstractDriver) ResetNodePassword(node *compute.Node, password string, opts *compute.PasswordResetOpts) error {
+	return nil
+}
+
 // Destroy an existing instance
 func (p *AbstractDriver) DestroyNode(node *compute.Node) error {
 	return nil
@@ -50,9 +60,9 @@ func (p *AbstractDriver) GetNodeState(node *compute.Node) (compute.NodeState, er
 	return compute.NodeState(""), nil
 }
 
-// Get the Console url for instance
-func (p *AbstractDriver) GetNodeConsole(node *compute.Node) (string, error) {
-	return "", nil
+// Get console session of instance, default vnc
+func (p *AbstractDriver) GetNodeConsole(node *compute.Node, opts *compute.ConsoleOpts) (*compute.ConsoleSession, error) {
+	return nil, nil
 }
 
 // Get the public IP address of instance
@@ -66,7 +76,7 @@ func (p *AbstractDriver) GetNodePrivateIp(node *compute.Node) (string, error) {
 }
 
 // List all available storage volumes for instance
-func (p *AbstractDriver) ListVolumes(node *compute.Node) ([]*compute.StorageVolume, error) {
+func (p *AbstractDriver) ListVolumes(node *compute.Node, filter *compute.VolumeFilter) ([]*compute.StorageVolume, error) {
 	return nil, nil
 }
 
@@ -81,7 +91,7 @@ func (p *AbstractDriver) DetachVolume(node *compute.Node, volume *compute.Storag
 }
 
 // List all snapshots for instance
-func (p *AbstractDriver) ListSnapshots(node *compute.Node) ([]*compute.VolumeSnapshot, error) {
+func (p *AbstractDriver) ListSnapshots(node *compute.Node, filter *compute.SnapshotFilter) ([]*compute.VolumeSnapshot, error) {
 	return nil, nil
 }
 
@@ -101,7 +111,7 @@ func (p *AbstractDriver) ApplySnapshot(node *compute.Node, snapshot *compute.Vol
 }
 
 // List all available images for instance
-func (p *AbstractDriver) ListImages() ([]*compute.NodeImage, error) {
+func (p *AbstractDriver) ListImages(filter *compute.ImageFilter) ([]*compute.NodeImage, error) {
 	return nil, nil
 }
 
@@ -124,3 +134,43 @@ func (p *AbstractDriver) ResizeNode(node *compute.Node, opts *compute.NodeResize
 func (p *AbstractDriver) ListLocations() ([]*compute.Location, error) {
 	return nil, nil
 }
+
+// Renew prepaid instance by months, one month by default
+func (p *AbstractDriver) RenewNode(node *compute.Node, period int) error {
+	return nil
+}
+
+// Enable or disable auto renewal of prepaid instance
+func (p *AbstractDriver) SetAutoRenew(node *compute.Node, enabled bool) error {
+	return nil
+}
+
+// Switch instance between prepaid and postpaid, period in months for prepaid, one month by default
+func (p *AbstractDriver) ModifyChargeType(node *compute.Node, chargeType compute.ChargeType, period int) error {
+	return nil
+}
+
+// Modify public bandwidth in Mbps, empty charge type keeps the current one
+func (p *AbstractDriver) ModifyNodeBandwidth(node *compute.Node, bandwidth int, chargeType compute.InternetChargeType) error {
+	return nil
+}
+
+// Run script on instances through cloud agent
+func (p *AbstractDriver) RunCommand(nodes []*compute.Node, script string, opts *compute.CommandOpts) (*compute.CommandInvocation, error) {
+	return nil, nil
+}
+
+// Get per instance results of command invocation
+func (p *AbstractDriver) GetCommandResults(invocation *compute.CommandInvocation) ([]*compute.CommandResult, error) {
+	return nil, nil
+}
+
+// Add or overwrite tags of resources
+func (p *AbstractDriver) TagResources(resourceType compute.ResourceType, ids []string, tags map[string]string) error {
+	return nil
+}
+
+// Remove tags from resources by key
+func (p *AbstractDriver) UntagResources(resourceType compute.ResourceType, ids []string, keys []string) error {
+	return nil
+}
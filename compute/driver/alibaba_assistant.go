@@ -0,0 +1,35 @@
+package drivers
+
+import (
+	"github.com/rehiy/cloudgo/compute"
+)
+
+// 云助手命令类型
+
+var aliyunCommandTypes = map[compute.CommandType]string{
+	"":                            "RunShellScript",
+	compute.CommandTypeSHELL:      "RunShellScript",
+	compute.CommandTypePOWERSHELL: "RunPowerShellScript",
+	compute.CommandTypeBAT:        "RunBatScript",
+}
+
+// 云助手执行状态
+
+func aliyunCommandStatus(status string) compute.CommandStatus {
+
+	switch status {
+	case "Pending", "Scheduled":
+		return compute.CommandStatusPENDING
+	case "Running", "Stopping":
+		return compute.CommandStatusRUNNING
+	case "Success", "Finished":
+		return compute.CommandStatusSUCCESS
+	case "Timeout":
+		return compute.CommandStatusTIMEOUT
+	case "Stopped", "Cancelled", "Terminated":
+		return compute.CommandStatusCANCELLED
+	}
+
+	return compute.CommandStatusFAILED
+
+}
@@ -1,6 +1,12 @@
 package drivers
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/rehiy/cloudgo/compute"
 	"github.com/rehiy/cloudgo/provider"
 	"github.com/rehiy/cloudgo/provider/alibaba"
@@ -9,6 +15,16 @@ import (
 	"github.com/alibabacloud-go/tea/tea"
 )
 
+// 实例状态映射
+
+var ecsNodeStates = map[string]compute.NodeState{
+	"Pending":  compute.NodeStatePENDING,
+	"Starting": compute.NodeStateSTARTING,
+	"Running":  compute.NodeStateRUNNING,
+	"Stopping": compute.NodeStateSTOPPING,
+	"Stopped":  compute.NodeStateSTOPPED,
+}
+
 type AlibabaEcsDriver struct {
 	client *alibaba.Client
 	ecs    *ecs.Client
@@ -25,42 +41,78 @@ func NewAlibabaEcsDriver(rq *provider.ReqeustParam) *AlibabaEcsDriver {
 }
 
 // List all instance
-func (p *AlibabaEcsDriver) ListNodes() ([]*compute.Node, error) {
+func (p *AlibabaEcsDriver) ListNodes(filter *compute.NodeFilter) ([]*compute.Node, error) {
 
-	resp, err := p.ecs.DescribeInstances(&ecs.DescribeInstancesRequest{})
+	request := &ecs.DescribeInstancesRequest{
+		RegionId: tea.String(p.rq.RegionId),
+		PageSize: tea.Int32(100),
+	}
 
-	if err != nil {
-		return nil, err
+	if filter != nil {
+		if len(filter.Ids) > 0 {
+			request.InstanceIds = tea.String(p.jsonList(filter.Ids...))
+		}
+		if filter.Name != "" {
+			request.InstanceName = tea.String(filter.Name)
+		} else if filter.NamePrefix != "" {
+			request.InstanceName = tea.String(filter.NamePrefix + "*")
+		}
+		if len(filter.States) == 1 {
+			for status, state := range ecsNodeStates {
+				if state == filter.States[0] {
+					request.Status = tea.String(status)
+				}
+			}
+		}
+		if filter.Zone != "" {
+			request.ZoneId = tea.String(filter.Zone)
+		}
+		if filter.VpcId != "" {
+			request.VpcId = tea.String(filter.VpcId)
+		}
+		if filter.PrivateIp != "" {
+			request.PrivateIpAddresses = tea.String(p.jsonList(filter.PrivateIp))
+		}
+		if filter.PublicIp != "" {
+			request.PublicIpAddresses = tea.String(p.jsonList(filter.PublicIp))
+		}
+		if filter.ImageId != "" {
+			request.ImageId = tea.String(filter.ImageId)
+		}
+		for k, v := range filter.Tags {
+			request.Tag = append(request.Tag, &ecs.DescribeInstancesRequestTag{
+				Key: tea.String(k), Value: tea.String(v),
+			})
+		}
 	}
 
 	var nodes []*compute.Node
 
-	for _, instance := range resp.Body.Instances.Instance {
+	for page := int32(1); ; page++ {
 
-		// TODO: fix mapping
-		state := compute.NodeState(*instance.Status)
-		osType := compute.OSType(*instance.OSType)
+		request.PageNumber = tea.Int32(page)
+		resp, err := p.ecs.DescribeInstances(request)
 
-		node := &compute.Node{
-			Id:        *instance.InstanceId,
-			Name:      *instance.InstanceName,
-			State:     state,
-			PublicIp:  *instance.PublicIpAddress.IpAddress[0],
-			PrivateIp: *instance.VpcAttributes.PrivateIpAddress.IpAddress[0],
-			Size: &compute.NodeSize{
-				Id: *instance.InstanceType,
-			},
-			Image: &compute.NodeImage{
-				Id:     *instance.ImageId,
-				Name:   *instance.OSName,
-				OSType: osType,
-			},
-			Location: &compute.Location{
-				Id: *instance.ZoneId,
-			},
+		if err != nil {
+			return nil, err
+		}
+
+		list := []*compute.Node{}
+		for _, instance := range resp.Body.Instances.Instance {
+			if node := p.toNode(instance); filter.Match(node) {
+				list = append(list, node)
+			}
+		}
+
+		if err := p.fillAutoRenew(list); err != nil {
+			return nil, err
 		}
 
-		nodes = append(nodes, node)
+		nodes = append(nodes, list...)
+
+		if len(resp.Body.Instances.Instance) < 100 || page*100 >= tea.Int32Value(resp.Body.TotalCount) {
+			break
+		}
 
 	}
 
@@ -72,6 +124,7 @@ func (p *AlibabaEcsDriver) ListNodes() ([]*compute.Node, error) {
 func (p *AlibabaEcsDriver) DetailNode(id string) (*compute.Node, error) {
 
 	resp, err := p.ecs.DescribeInstances(&ecs.DescribeInstancesRequest{
+		RegionId:    tea.String(p.rq.RegionId),
 		InstanceIds: tea.String(`["` + id + `"]`),
 	})
 
@@ -79,29 +132,14 @@ func (p *AlibabaEcsDriver) DetailNode(id string) (*compute.Node, error) {
 		return nil, err
 	}
 
-	instance := resp.Body.Instances.Instance[0]
+	if len(resp.Body.Instances.Instance) == 0 {
+		return nil, nil
+	}
 
-	// TODO: fix mapping
-	state := compute.NodeState(*instance.Status)
-	osType := compute.OSType(*instance.OSType)
+	node := p.toNode(resp.Body.Instances.Instance[0])
 
-	node := &compute.Node{
-		Id:        *instance.InstanceId,
-		Name:      *instance.InstanceName,
-		State:     state,
-		PublicIp:  *instance.PublicIpAddress.IpAddress[0],
-		PrivateIp: *instance.VpcAttributes.PrivateIpAddress.IpAddress[0],
-		Size: &compute.NodeSize{
-			Id: *instance.InstanceType,
-		},
-		Image: &compute.NodeImage{
-			Id:     *instance.ImageId,
-			Name:   *instance.OSName,
-			OSType: osType,
-		},
-		Location: &compute.Location{
-			Id: *instance.ZoneId,
-		},
+	if err := p.fillAutoRenew([]*compute.Node{node}); err != nil {
+		return nil, err
 	}
 
 	return node, nil
@@ -111,12 +149,55 @@ func (p *AlibabaEcsDriver) DetailNode(id string) (*compute.Node, error) {
 // Create new instance
 func (p *AlibabaEcsDriver) CreateNode(opts *compute.NodeCreateOpts) (*compute.Node, error) {
 
-	resp, err := p.ecs.CreateInstance(&ecs.CreateInstanceRequest{
+	if opts.Size == nil || opts.Image == nil || opts.Location == nil {
+		return nil, compute.InvalidParameterError
+	}
+
+	request := &ecs.CreateInstanceRequest{
 		InstanceName: tea.String(opts.Name),
 		InstanceType: tea.String(opts.Size.Id),
 		ImageId:      tea.String(opts.Image.Id),
 		ZoneId:       tea.String(opts.Location.Id),
-	})
+	}
+
+	if opts.UserData != nil {
+		userData, err := p.userData(opts.UserData)
+		if err != nil {
+			return nil, err
+		}
+		request.UserData = tea.String(userData)
+	}
+
+	switch opts.ChargeType {
+	case "", compute.ChargeTypePOSTPAID:
+	case compute.ChargeTypePREPAID:
+		period := opts.Period
+		if period <= 0 {
+			period = 1
+		}
+		request.InstanceChargeType = tea.String("PrePaid")
+		request.Period = tea.Int32(int32(period))
+		request.PeriodUnit = tea.String("Month")
+	default:
+		return nil, compute.NotSupportedError
+	}
+
+	switch opts.SpotStrategy {
+	case compute.SpotStrategyLIMIT:
+		request.SpotStrategy = tea.String("SpotWithPriceLimit")
+		request.SpotPriceLimit = tea.Float32(float32(opts.SpotPriceLimit))
+	case compute.SpotStrategyAUTO:
+		request.SpotStrategy = tea.String("SpotAsPriceGo")
+	}
+
+	for k, v := range opts.Tags {
+		request.Tag = append(request.Tag, &ecs.CreateInstanceRequestTag{
+			Key:   tea.String(k),
+			Value: tea.String(v),
+		})
+	}
+
+	resp, err := p.ecs.CreateInstance(request)
 
 	if err != nil {
 		return nil, err
@@ -125,15 +206,85 @@ func (p *AlibabaEcsDriver) CreateNode(opts *compute.NodeCreateOpts) (*compute.No
 	instance := resp.Body.InstanceId
 
 	node := &compute.Node{
-		Id:   *instance,
-		Name: opts.Name,
-		Size: opts.Size,
+		Id:             *instance,
+		Name:           opts.Name,
+		Size:           opts.Size,
+		Spot:           opts.SpotStrategy != compute.SpotStrategyNONE,
+		SpotPriceLimit: opts.SpotPriceLimit,
+		Tags:           map[string]string{},
+	}
+
+	for k, v := range opts.Tags {
+		node.Tags[k] = v
 	}
 
 	return node, nil
 
 }
 
+// Update name, description or hostname of instance
+func (p *AlibabaEcsDriver) UpdateNode(node *compute.Node, attrs *compute.NodeUpdateAttrs) error {
+
+	request := &ecs.ModifyInstanceAttributeRequest{
+		InstanceId: tea.String(node.Id),
+	}
+
+	if attrs.Name != "" {
+		request.InstanceName = tea.String(attrs.Name)
+	}
+	if attrs.Description != "" {
+		request.Description = tea.String(attrs.Description)
+	}
+	if attrs.Hostname != "" {
+		request.HostName = tea.String(attrs.Hostname)
+	}
+
+	if _, err := p.ecs.ModifyInstanceAttribute(request); err != nil {
+		return err
+	}
+
+	if attrs.Name != "" {
+		node.Name = attrs.Name
+	}
+
+	// 主机名需重启后生效
+	if attrs.Hostname != "" && attrs.Reboot && node.State == compute.NodeStateRUNNING {
+		return p.RebootNode(node)
+	}
+
+	return nil
+
+}
+
+// Reset login password of instance
+func (p *AlibabaEcsDriver) ResetNodePassword(node *compute.Node, password string, opts *compute.PasswordResetOpts) error {
+
+	if opts == nil {
+		opts = &compute.PasswordResetOpts{}
+	}
+
+	if opts.Username != "" {
+		return compute.NotSupportedError
+	}
+
+	_, err := p.ecs.ModifyInstanceAttribute(&ecs.ModifyInstanceAttributeRequest{
+		InstanceId: tea.String(node.Id),
+		Password:   tea.String(password),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// 密码需重启后生效
+	if (opts.Reboot || opts.ForceStop) && node.State == compute.NodeStateRUNNING {
+		return p.RebootNode(node)
+	}
+
+	return nil
+
+}
+
 // Destroy an existing instance
 func (p *AlibabaEcsDriver) DestroyNode(node *compute.Node) error {
 
@@ -189,27 +340,60 @@ func (p *AlibabaEcsDriver) GetNodeState(node *compute.Node) (compute.NodeState,
 		return "", err
 	}
 
-	instance := resp.Body.Instances.Instance[0]
-
-	// TODO: fix mapping
-	state := compute.NodeState(*instance.Status)
+	if len(resp.Body.Instances.Instance) == 0 {
+		return "", nil
+	}
 
-	return state, nil
+	return p.toNode(resp.Body.Instances.Instance[0]).State, nil
 
 }
 
-// Get the Console url for instance
-func (p *AlibabaEcsDriver) GetNodeConsole(node *compute.Node) (string, error) {
+// Get console session of instance, only vnc is supported
+// VNC password is required by page, it will be reset when opts.Password is set
+func (p *AlibabaEcsDriver) GetNodeConsole(node *compute.Node, opts *compute.ConsoleOpts) (*compute.ConsoleSession, error) {
+
+	if opts == nil {
+		opts = &compute.ConsoleOpts{}
+	}
+
+	if opts.Type != "" && opts.Type != compute.ConsoleTypeVNC {
+		return nil, compute.NotSupportedError
+	}
+
+	if opts.Password != "" {
+		_, err := p.ecs.ModifyInstanceVncPasswd(&ecs.ModifyInstanceVncPasswdRequest{
+			RegionId:    tea.String(p.rq.RegionId),
+			InstanceId:  tea.String(node.Id),
+			VncPassword: tea.String(opts.Password),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	resp, err := p.ecs.DescribeInstanceVncUrl(&ecs.DescribeInstanceVncUrlRequest{
+		RegionId:   tea.String(p.rq.RegionId),
 		InstanceId: tea.String(node.Id),
 	})
 
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	// 链接有效期 15 秒，仅可使用一次
+	session := &compute.ConsoleSession{
+		Type:      compute.ConsoleTypeVNC,
+		Provider:  "alibaba_ecs",
+		NodeId:    node.Id,
+		Url:       tea.StringValue(resp.Body.VncUrl),
+		Password:  opts.Password,
+		ExpiredAt: time.Now().Add(15 * time.Second),
+		Extra: map[string]interface{}{
+			"IsWindows": node.Image != nil && node.Image.OSType == compute.Windows,
+		},
 	}
 
-	return *resp.Body.VncUrl, nil
+	return session, nil
 
 }
 
@@ -250,11 +434,22 @@ func (p *AlibabaEcsDriver) GetNodePrivateIp(node *compute.Node) (string, error)
 }
 
 // List all available storage volumes for instance
-func (p *AlibabaEcsDriver) ListVolumes(node *compute.Node) ([]*compute.StorageVolume, error) {
+func (p *AlibabaEcsDriver) ListVolumes(node *compute.Node, filter *compute.VolumeFilter) ([]*compute.StorageVolume, error) {
 
-	resp, err := p.ecs.DescribeDisks(&ecs.DescribeDisksRequest{
+	request := &ecs.DescribeDisksRequest{
+		RegionId:   tea.String(p.rq.RegionId),
 		InstanceId: tea.String(node.Id),
-	})
+	}
+
+	if filter != nil {
+		for k, v := range filter.Tags {
+			request.Tag = append(request.Tag, &ecs.DescribeDisksRequestTag{
+				Key: tea.String(k), Value: tea.String(v),
+			})
+		}
+	}
+
+	resp, err := p.ecs.DescribeDisks(request)
 
 	if err != nil {
 		return nil, err
@@ -264,10 +459,19 @@ func (p *AlibabaEcsDriver) ListVolumes(node *compute.Node) ([]*compute.StorageVo
 
 	for _, disk := range resp.Body.Disks.Disk {
 
+		tags := map[string]string{}
+		if disk.Tags != nil {
+			for _, tag := range disk.Tags.Tag {
+				tags[tea.StringValue(tag.TagKey)] = tea.StringValue(tag.TagValue)
+			}
+		}
+
 		volume := &compute.StorageVolume{
 			Id:   *disk.DiskId,
 			Name: *disk.DiskName,
+			Type: tea.StringValue(disk.Category),
 			Size: int(*disk.Size),
+			Tags: tags,
 		}
 
 		volumes = append(volumes, volume)
@@ -303,11 +507,22 @@ func (p *AlibabaEcsDriver) DetachVolume(node *compute.Node, volume *compute.Stor
 }
 
 // List all snapshots for instance
-func (p *AlibabaEcsDriver) ListSnapshots(node *compute.Node) ([]*compute.VolumeSnapshot, error) {
+func (p *AlibabaEcsDriver) ListSnapshots(node *compute.Node, filter *compute.SnapshotFilter) ([]*compute.VolumeSnapshot, error) {
 
-	resp, err := p.ecs.DescribeDisks(&ecs.DescribeDisksRequest{
+	request := &ecs.DescribeSnapshotsRequest{
+		RegionId:   tea.String(p.rq.RegionId),
 		InstanceId: tea.String(node.Id),
-	})
+	}
+
+	if filter != nil {
+		for k, v := range filter.Tags {
+			request.Tag = append(request.Tag, &ecs.DescribeSnapshotsRequestTag{
+				Key: tea.String(k), Value: tea.String(v),
+			})
+		}
+	}
+
+	resp, err := p.ecs.DescribeSnapshots(request)
 
 	if err != nil {
 		return nil, err
@@ -315,12 +530,24 @@ func (p *AlibabaEcsDriver) ListSnapshots(node *compute.Node) ([]*compute.VolumeS
 
 	var snapshots []*compute.VolumeSnapshot
 
-	for _, disk := range resp.Body.Disks.Disk {
+	for _, item := range resp.Body.Snapshots.Snapshot {
+
+		tags := map[string]string{}
+		if item.Tags != nil {
+			for _, tag := range item.Tags.Tag {
+				tags[tea.StringValue(tag.TagKey)] = tea.StringValue(tag.TagValue)
+			}
+		}
+
+		size, _ := strconv.Atoi(tea.StringValue(item.SourceDiskSize))
+		created, _ := time.Parse(time.RFC3339, tea.StringValue(item.CreationTime))
 
 		snapshot := &compute.VolumeSnapshot{
-			Id:   *disk.DiskId,
-			Name: *disk.DiskName,
-			Size: int(*disk.Size),
+			Id:        tea.StringValue(item.SnapshotId),
+			Name:      tea.StringValue(item.SnapshotName),
+			Size:      size,
+			CreatedAt: created,
+			Tags:      tags,
 		}
 
 		snapshots = append(snapshots, snapshot)
@@ -376,11 +603,22 @@ func (p *AlibabaEcsDriver) ApplySnapshot(node *compute.Node, snapshot *compute.V
 }
 
 // List all available images for instance
-func (p *AlibabaEcsDriver) ListImages() ([]*compute.NodeImage, error) {
+func (p *AlibabaEcsDriver) ListImages(filter *compute.ImageFilter) ([]*compute.NodeImage, error) {
 
-	resp, err := p.ecs.DescribeImages(&ecs.DescribeImagesRequest{
+	request := &ecs.DescribeImagesRequest{
+		RegionId:        tea.String(p.rq.RegionId),
 		ImageOwnerAlias: tea.String("self"),
-	})
+	}
+
+	if filter != nil {
+		for k, v := range filter.Tags {
+			request.Tag = append(request.Tag, &ecs.DescribeImagesRequestTag{
+				Key: tea.String(k), Value: tea.String(v),
+			})
+		}
+	}
+
+	resp, err := p.ecs.DescribeImages(request)
 
 	if err != nil {
 		return nil, err
@@ -390,9 +628,17 @@ func (p *AlibabaEcsDriver) ListImages() ([]*compute.NodeImage, error) {
 
 	for _, image := range resp.Body.Images.Image {
 
+		tags := map[string]string{}
+		if image.Tags != nil {
+			for _, tag := range image.Tags.Tag {
+				tags[tea.StringValue(tag.TagKey)] = tea.StringValue(tag.TagValue)
+			}
+		}
+
 		images = append(images, &compute.NodeImage{
 			Id:   *image.ImageId,
 			Name: *image.ImageName,
+			Tags: tags,
 		})
 
 	}
@@ -421,16 +667,27 @@ func (p *AlibabaEcsDriver) ListSizes() ([]*compute.NodeSize, error) {
 
 	for _, instanceType := range resp.Body.InstanceTypes.InstanceType {
 
-		arch := compute.Architecture(*instanceType.CpuArchitecture)
+		arch := compute.ParseArchitecture(tea.StringValue(instanceType.CpuArchitecture))
+
+		// MemorySize 单位 GiB，本地盘容量单位 GiB，内网带宽单位 Kbps
+		// 公网带宽在购买时选择，规格不含公网带宽，也不返回价格
+		ram := tea.Float32Value(instanceType.MemorySize) * 1024
+		disk := tea.Int64Value(instanceType.LocalStorageCapacity) * int64(tea.Int32Value(instanceType.LocalStorageAmount))
+		bandwidth := tea.Int32Value(instanceType.InstanceBandwidthTx) / 1000
 
 		sizes = append(sizes, &compute.NodeSize{
-			Id:           *instanceType.InstanceTypeId,
-			Name:         *instanceType.InstanceTypeFamily,
+			Id:           tea.StringValue(instanceType.InstanceTypeId),
+			Name:         tea.StringValue(instanceType.InstanceTypeFamily),
+			Provider:     "alibaba_ecs",
+			Region:       p.rq.RegionId,
 			Architecture: arch,
-			Gpu:          int(*instanceType.GPUAmount),
-			Cpu:          int(*instanceType.CpuCoreCount),
-			Ram:          int(*instanceType.MemorySize),
-			Disk:         int(*instanceType.DiskQuantity),
+			Gpu:          int(tea.Int32Value(instanceType.GPUAmount)),
+			Cpu:          int(tea.Int32Value(instanceType.CpuCoreCount)),
+			Ram:          int(ram),
+			Disk:         int(disk),
+			Extra: map[string]interface{}{
+				"InstanceBandwidth": int(bandwidth),
+			},
 		})
 
 	}
@@ -474,3 +731,499 @@ func (p *AlibabaEcsDriver) ListLocations() ([]*compute.Location, error) {
 	return locations, nil
 
 }
+
+// Renew prepaid instance by months, one month by default
+func (p *AlibabaEcsDriver) RenewNode(node *compute.Node, period int) error {
+
+	if period <= 0 {
+		period = 1
+	}
+
+	_, err := p.ecs.RenewInstance(&ecs.RenewInstanceRequest{
+		InstanceId: tea.String(node.Id),
+		Period:     tea.Int32(int32(period)),
+		PeriodUnit: tea.String("Month"),
+	})
+
+	return err
+
+}
+
+// Enable or disable auto renewal of prepaid instance
+func (p *AlibabaEcsDriver) SetAutoRenew(node *compute.Node, enabled bool) error {
+
+	request := &ecs.ModifyInstanceAutoRenewAttributeRequest{
+		RegionId:      tea.String(p.rq.RegionId),
+		InstanceId:    tea.String(node.Id),
+		AutoRenew:     tea.Bool(enabled),
+		RenewalStatus: tea.String("Normal"),
+	}
+
+	if enabled {
+		request.Duration = tea.Int32(1)
+		request.PeriodUnit = tea.String("Month")
+		request.RenewalStatus = tea.String("AutoRenewal")
+	}
+
+	_, err := p.ecs.ModifyInstanceAutoRenewAttribute(request)
+
+	return err
+
+}
+
+// Switch instance between prepaid and postpaid, period in months for prepaid, one month by default
+func (p *AlibabaEcsDriver) ModifyChargeType(node *compute.Node, chargeType compute.ChargeType, period int) error {
+
+	if period <= 0 {
+		period = 1
+	}
+
+	request := &ecs.ModifyInstanceChargeTypeRequest{
+		RegionId:    tea.String(p.rq.RegionId),
+		InstanceIds: tea.String(p.jsonList(node.Id)),
+		AutoPay:     tea.Bool(true),
+	}
+
+	switch chargeType {
+	case compute.ChargeTypePREPAID:
+		request.InstanceChargeType = tea.String("PrePaid")
+		request.Period = tea.Int32(int32(period))
+		request.PeriodUnit = tea.String("Month")
+	case compute.ChargeTypePOSTPAID:
+		request.InstanceChargeType = tea.String("PostPaid")
+	default:
+		return compute.NotSupportedError
+	}
+
+	_, err := p.ecs.ModifyInstanceChargeType(request)
+
+	return err
+
+}
+
+// Modify public bandwidth in Mbps, empty charge type keeps the current one
+func (p *AlibabaEcsDriver) ModifyNodeBandwidth(node *compute.Node, bandwidth int, chargeType compute.InternetChargeType) error {
+
+	request := &ecs.ModifyInstanceNetworkSpecRequest{
+		InstanceId:              tea.String(node.Id),
+		InternetMaxBandwidthOut: tea.Int32(int32(bandwidth)),
+		AutoPay:                 tea.Bool(true),
+	}
+
+	if chargeType != "" {
+		networkChargeType, ok := ecsInternetChargeTypes[chargeType]
+		if !ok {
+			return compute.NotSupportedError
+		}
+		request.NetworkChargeType = tea.String(networkChargeType)
+	}
+
+	_, err := p.ecs.ModifyInstanceNetworkSpec(request)
+
+	if err == nil {
+		node.Bandwidth = bandwidth
+		if chargeType != "" {
+			node.InternetCharge = chargeType
+		}
+	}
+
+	return err
+
+}
+
+// Run script on instances through cloud agent
+func (p *AlibabaEcsDriver) RunCommand(nodes []*compute.Node, script string, opts *compute.CommandOpts) (*compute.CommandInvocation, error) {
+
+	if opts == nil {
+		opts = &compute.CommandOpts{}
+	}
+
+	ids := []string{}
+	for _, node := range nodes {
+		ids = append(ids, node.Id)
+	}
+
+	request := &ecs.RunCommandRequest{
+		RegionId:        tea.String(p.rq.RegionId),
+		InstanceId:      tea.StringSlice(ids),
+		Type:            tea.String(aliyunCommandTypes[opts.Type]),
+		CommandContent:  tea.String(script),
+		ContentEncoding: tea.String("PlainText"),
+	}
+
+	if opts.Timeout > 0 {
+		request.Timeout = tea.Int64(int64(opts.Timeout))
+	}
+	if opts.WorkingDir != "" {
+		request.WorkingDir = tea.String(opts.WorkingDir)
+	}
+	if opts.Username != "" {
+		request.Username = tea.String(opts.Username)
+	}
+
+	resp, err := p.ecs.RunCommand(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	invocation := &compute.CommandInvocation{
+		Id:      tea.StringValue(resp.Body.InvokeId),
+		NodeIds: ids,
+	}
+
+	return invocation, nil
+
+}
+
+// Get per instance results of command invocation
+func (p *AlibabaEcsDriver) GetCommandResults(invocation *compute.CommandInvocation) ([]*compute.CommandResult, error) {
+
+	request := &ecs.DescribeInvocationResultsRequest{
+		RegionId:        tea.String(p.rq.RegionId),
+		InvokeId:        tea.String(invocation.Id),
+		ContentEncoding: tea.String("PlainText"),
+		PageSize:        tea.Int64(50),
+	}
+
+	results := []*compute.CommandResult{}
+
+	for page := int64(1); ; page++ {
+
+		request.PageNumber = tea.Int64(page)
+		resp, err := p.ecs.DescribeInvocationResults(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items := resp.Body.Invocation.InvocationResults.InvocationResult
+
+		for _, item := range items {
+			results = append(results, &compute.CommandResult{
+				NodeId:   tea.StringValue(item.InstanceId),
+				Status:   aliyunCommandStatus(tea.StringValue(item.InvocationStatus)),
+				ExitCode: int(tea.Int64Value(item.ExitCode)),
+				Stdout:   tea.StringValue(item.Output),
+				Stderr:   tea.StringValue(item.ErrorInfo),
+			})
+		}
+
+		if len(items) < 50 || page*50 >= tea.Int64Value(resp.Body.Invocation.TotalCount) {
+			break
+		}
+
+	}
+
+	return results, nil
+
+}
+
+// Add or overwrite tags of resources
+func (p *AlibabaEcsDriver) TagResources(resourceType compute.ResourceType, ids []string, tags map[string]string) error {
+
+	request := &ecs.TagResourcesRequest{
+		RegionId:     tea.String(p.rq.RegionId),
+		ResourceType: tea.String(p.resourceType(resourceType)),
+		ResourceId:   tea.StringSlice(ids),
+	}
+
+	for k, v := range tags {
+		request.Tag = append(request.Tag, &ecs.TagResourcesRequestTag{
+			Key: tea.String(k), Value: tea.String(v),
+		})
+	}
+
+	_, err := p.ecs.TagResources(request)
+
+	return err
+
+}
+
+// Remove tags from resources by key
+func (p *AlibabaEcsDriver) UntagResources(resourceType compute.ResourceType, ids []string, keys []string) error {
+
+	_, err := p.ecs.UntagResources(&ecs.UntagResourcesRequest{
+		RegionId:     tea.String(p.rq.RegionId),
+		ResourceType: tea.String(p.resourceType(resourceType)),
+		ResourceId:   tea.StringSlice(ids),
+		TagKey:       tea.StringSlice(keys),
+	})
+
+	return err
+
+}
+
+// Query current spot prices of size, empty location means all zones
+func (p *AlibabaEcsDriver) ListSpotPrices(size *compute.NodeSize, location *compute.Location) ([]*compute.SpotPrice, error) {
+
+	request := &ecs.DescribeSpotPriceHistoryRequest{
+		RegionId:     tea.String(p.rq.RegionId),
+		InstanceType: tea.String(size.Id),
+		NetworkType:  tea.String("vpc"),
+		StartTime:    tea.String(time.Now().UTC().Add(-time.Hour).Format("2006-01-02T15:04:05Z")),
+	}
+
+	if location != nil && location.Id != "" {
+		request.ZoneId = tea.String(location.Id)
+	}
+
+	resp, err := p.ecs.DescribeSpotPriceHistory(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// 按可用区保留最新价格
+	latest := map[string]*compute.SpotPrice{}
+	prices := []*compute.SpotPrice{}
+
+	for _, item := range resp.Body.SpotPrices.SpotPriceType {
+		stamp, _ := time.Parse(time.RFC3339, tea.StringValue(item.Timestamp))
+		price := &compute.SpotPrice{
+			SizeId:      tea.StringValue(item.InstanceType),
+			Zone:        tea.StringValue(item.ZoneId),
+			Price:       float64(tea.Float32Value(item.SpotPrice)),
+			OriginPrice: float64(tea.Float32Value(item.OriginPrice)),
+			Timestamp:   stamp,
+			Extra:       map[string]interface{}{"Currency": tea.StringValue(resp.Body.Currency)},
+		}
+		if prev, ok := latest[price.Zone]; ok {
+			if prev.Timestamp.Before(stamp) {
+				*prev = *price
+			}
+			continue
+		}
+		latest[price.Zone] = price
+		prices = append(prices, price)
+	}
+
+	return prices, nil
+
+}
+
+// List pending interruption notices of spot instances
+func (p *AlibabaEcsDriver) ListSpotInterruptions(nodes []*compute.Node) ([]*compute.SpotInterruption, error) {
+
+	ids := []string{}
+	for _, node := range nodes {
+		ids = append(ids, node.Id)
+	}
+
+	request := &ecs.DescribeInstanceHistoryEventsRequest{
+		RegionId:                 tea.String(p.rq.RegionId),
+		ResourceId:               tea.StringSlice(ids),
+		InstanceEventType:        tea.StringSlice([]string{"Instance:PreemptionAndRecycle"}),
+		InstanceEventCycleStatus: tea.StringSlice([]string{"Scheduled", "Executing"}),
+		PageNumber:               tea.Int32(1),
+		PageSize:                 tea.Int32(100),
+	}
+
+	notices := []*compute.SpotInterruption{}
+
+	for {
+		resp, err := p.ecs.DescribeInstanceHistoryEvents(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		events := resp.Body.InstanceSystemEventSet.InstanceSystemEventType
+
+		for _, event := range events {
+			notBefore, _ := time.Parse(time.RFC3339, tea.StringValue(event.NotBefore))
+			notice := &compute.SpotInterruption{
+				NodeId: tea.StringValue(event.InstanceId),
+				Action: "release",
+				Time:   notBefore,
+				Extra: map[string]interface{}{
+					"EventId": tea.StringValue(event.EventId),
+					"Reason":  tea.StringValue(event.Reason),
+				},
+			}
+			notices = append(notices, notice)
+		}
+
+		if len(notices) >= int(tea.Int32Value(resp.Body.TotalCount)) || len(events) == 0 {
+			break
+		}
+
+		request.PageNumber = tea.Int32(tea.Int32Value(request.PageNumber) + 1)
+	}
+
+	return notices, nil
+
+}
+
+// 编码实例自定义数据，Base64 后最大 32 KB
+func (p *AlibabaEcsDriver) userData(data *compute.UserData) (string, error) {
+
+	content, err := data.Render()
+	if err != nil {
+		return "", err
+	}
+
+	// Windows 实例使用 [powershell] 标识脚本类型
+	if data.Windows && data.Script == "" {
+		content = "[powershell]" + strings.TrimPrefix(content, "#ps1_sysnative")
+	}
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(content))
+
+	if len(encoded) > 32*1024 {
+		return "", compute.UserDataTooLargeError
+	}
+
+	return encoded, nil
+
+}
+
+// 补充包年包月实例的自动续费状态
+func (p *AlibabaEcsDriver) fillAutoRenew(nodes []*compute.Node) error {
+
+	ids := []string{}
+	index := map[string]*compute.Node{}
+
+	for _, node := range nodes {
+		if node.ChargeType == compute.ChargeTypePREPAID {
+			ids = append(ids, node.Id)
+			index[node.Id] = node
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	resp, err := p.ecs.DescribeInstanceAutoRenewAttribute(&ecs.DescribeInstanceAutoRenewAttributeRequest{
+		RegionId:   tea.String(p.rq.RegionId),
+		InstanceId: tea.String(strings.Join(ids, ",")),
+		PageSize:   tea.String("100"),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	for _, item := range resp.Body.InstanceRenewAttributes.InstanceRenewAttribute {
+		if node, ok := index[tea.StringValue(item.InstanceId)]; ok {
+			node.AutoRenew = tea.BoolValue(item.AutoRenewEnabled)
+		}
+	}
+
+	return nil
+
+}
+
+// 生成 JSON 数组参数
+func (p *AlibabaEcsDriver) jsonList(items ...string) string {
+
+	data, _ := json.Marshal(items)
+	return string(data)
+
+}
+
+// 资源类型名称
+func (p *AlibabaEcsDriver) resourceType(resourceType compute.ResourceType) string {
+
+	switch resourceType {
+	case compute.ResourceVolume:
+		return "disk"
+	case compute.ResourceSnapshot:
+		return "snapshot"
+	case compute.ResourceImage:
+		return "image"
+	}
+
+	return "instance"
+
+}
+
+// 转换实例信息
+func (p *AlibabaEcsDriver) toNode(instance *ecs.DescribeInstancesResponseBodyInstancesInstance) *compute.Node {
+
+	state, ok := ecsNodeStates[tea.StringValue(instance.Status)]
+	if !ok {
+		state = compute.NodeStateUNKNOWN
+	}
+
+	// TODO: fix mapping
+	osType := compute.OSType(tea.StringValue(instance.OSType))
+
+	node := &compute.Node{
+		Id:    tea.StringValue(instance.InstanceId),
+		Name:  tea.StringValue(instance.InstanceName),
+		State: state,
+		Size: &compute.NodeSize{
+			Id:  tea.StringValue(instance.InstanceType),
+			Cpu: int(tea.Int32Value(instance.Cpu)),
+			Ram: int(tea.Int32Value(instance.Memory)),
+			Gpu: int(tea.Int32Value(instance.GPUAmount)),
+		},
+		Image: &compute.NodeImage{
+			Id:     tea.StringValue(instance.ImageId),
+			Name:   tea.StringValue(instance.OSName),
+			OSType: osType,
+		},
+		Location: &compute.Location{
+			Id: tea.StringValue(instance.ZoneId),
+		},
+		Tags: map[string]string{},
+	}
+
+	if instance.PublicIpAddress != nil && len(instance.PublicIpAddress.IpAddress) > 0 {
+		node.PublicIp = tea.StringValue(instance.PublicIpAddress.IpAddress[0])
+	} else if instance.EipAddress != nil {
+		node.PublicIp = tea.StringValue(instance.EipAddress.IpAddress)
+	}
+
+	if instance.VpcAttributes != nil && instance.VpcAttributes.PrivateIpAddress != nil {
+		if ips := instance.VpcAttributes.PrivateIpAddress.IpAddress; len(ips) > 0 {
+			node.PrivateIp = tea.StringValue(ips[0])
+		}
+	}
+
+	if instance.VpcAttributes != nil {
+		node.VpcId = tea.StringValue(instance.VpcAttributes.VpcId)
+	}
+
+	if instance.Tags != nil {
+		for _, tag := range instance.Tags.Tag {
+			node.Tags[tea.StringValue(tag.TagKey)] = tea.StringValue(tag.TagValue)
+		}
+	}
+
+	if created, err := time.Parse("2006-01-02T15:04Z", tea.StringValue(instance.CreationTime)); err == nil {
+		node.CreatedAt = created
+	}
+
+	node.Bandwidth = int(tea.Int32Value(instance.InternetMaxBandwidthOut))
+
+	switch tea.StringValue(instance.SpotStrategy) {
+	case "SpotWithPriceLimit", "SpotAsPriceGo":
+		node.Spot = true
+		node.SpotPriceLimit = float64(tea.Float32Value(instance.SpotPriceLimit))
+	}
+
+	switch tea.StringValue(instance.InternetChargeType) {
+	case "PayByTraffic":
+		node.InternetCharge = compute.InternetChargeBYTRAFFIC
+	case "PayByBandwidth":
+		node.InternetCharge = compute.InternetChargeBYBANDWIDTH
+	default:
+		node.InternetCharge = compute.InternetChargeUNKNOWN
+	}
+
+	switch tea.StringValue(instance.InstanceChargeType) {
+	case "PrePaid":
+		node.ChargeType = compute.ChargeTypePREPAID
+		node.ExpiredAt, _ = time.Parse("2006-01-02T15:04Z", tea.StringValue(instance.ExpiredTime))
+	case "PostPaid":
+		node.ChargeType = compute.ChargeTypePOSTPAID
+	default:
+		node.ChargeType = compute.ChargeTypeUNKNOWN
+	}
+
+	return node
+
+}
@@ -0,0 +1,223 @@
+package drivers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/rehiy/cloudgo/compute"
+
+	ecs "github.com/alibabacloud-go/ecs-20140526/v3/client"
+	"github.com/alibabacloud-go/tea/tea"
+)
+
+// 弹性公网 IP 状态映射
+
+var ecsAddressStates = map[string]compute.AddressState{
+	"Available":     compute.AddressStateAVAILABLE,
+	"InUse":         compute.AddressStateINUSE,
+	"Associating":   compute.AddressStateBINDING,
+	"Unassociating": compute.AddressStateUNBINDING,
+	"Releasing":     compute.AddressStatePENDING,
+}
+
+var ecsInternetChargeTypes = map[compute.InternetChargeType]string{
+	compute.InternetChargeBYTRAFFIC:   "PayByTraffic",
+	compute.InternetChargeBYBANDWIDTH: "PayByBandwidth",
+}
+
+// List all addresses with binding status
+func (p *AlibabaEcsDriver) ListAddresses() ([]*compute.Address, error) {
+
+	request := &ecs.DescribeEipAddressesRequest{
+		RegionId:   tea.String(p.rq.RegionId),
+		PageNumber: tea.Int32(1),
+		PageSize:   tea.Int32(100),
+	}
+
+	addresses := []*compute.Address{}
+
+	for {
+		resp, err := p.ecs.DescribeEipAddresses(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range resp.Body.EipAddresses.EipAddress {
+			addresses = append(addresses, p.toAddress(item))
+		}
+
+		if len(addresses) >= int(tea.Int32Value(resp.Body.TotalCount)) || len(resp.Body.EipAddresses.EipAddress) == 0 {
+			break
+		}
+
+		request.PageNumber = tea.Int32(tea.Int32Value(request.PageNumber) + 1)
+	}
+
+	return addresses, nil
+
+}
+
+// Allocate new address
+func (p *AlibabaEcsDriver) AllocateAddress(opts *compute.AddressCreateOpts) (*compute.Address, error) {
+
+	request := &ecs.AllocateEipAddressRequest{
+		RegionId: tea.String(p.rq.RegionId),
+	}
+
+	if opts.Bandwidth > 0 {
+		request.Bandwidth = tea.String(strconv.Itoa(opts.Bandwidth))
+	}
+	if opts.ChargeType != "" {
+		request.InternetChargeType = tea.String(ecsInternetChargeTypes[opts.ChargeType])
+	}
+	if opts.Line != "" {
+		request.ISP = tea.String(opts.Line)
+	}
+
+	resp, err := p.ecs.AllocateEipAddress(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	address := &compute.Address{
+		Id:         tea.StringValue(resp.Body.AllocationId),
+		Ip:         tea.StringValue(resp.Body.EipAddress),
+		State:      compute.AddressStateAVAILABLE,
+		Bandwidth:  opts.Bandwidth,
+		ChargeType: opts.ChargeType,
+		Line:       opts.Line,
+	}
+
+	return address, nil
+
+}
+
+// Bind address to instance
+func (p *AlibabaEcsDriver) AssociateAddress(address *compute.Address, node *compute.Node) error {
+
+	_, err := p.ecs.AssociateEipAddress(&ecs.AssociateEipAddressRequest{
+		RegionId:     tea.String(p.rq.RegionId),
+		AllocationId: tea.String(address.Id),
+		InstanceId:   tea.String(node.Id),
+		InstanceType: tea.String("EcsInstance"),
+	})
+
+	if err == nil {
+		address.NodeId = node.Id
+	}
+
+	return err
+
+}
+
+// Bind address to elastic network interface
+func (p *AlibabaEcsDriver) AssociateAddressEni(address *compute.Address, eniId string) error {
+
+	_, err := p.ecs.AssociateEipAddress(&ecs.AssociateEipAddressRequest{
+		RegionId:     tea.String(p.rq.RegionId),
+		AllocationId: tea.String(address.Id),
+		InstanceId:   tea.String(eniId),
+		InstanceType: tea.String("NetworkInterface"),
+	})
+
+	if err == nil {
+		address.EniId = eniId
+	}
+
+	return err
+
+}
+
+// Unbind address from instance or network interface
+func (p *AlibabaEcsDriver) DisassociateAddress(address *compute.Address) error {
+
+	request := &ecs.UnassociateEipAddressRequest{
+		RegionId:     tea.String(p.rq.RegionId),
+		AllocationId: tea.String(address.Id),
+		InstanceId:   tea.String(address.NodeId),
+		InstanceType: tea.String("EcsInstance"),
+	}
+
+	if address.EniId != "" {
+		request.InstanceId = tea.String(address.EniId)
+		request.InstanceType = tea.String("NetworkInterface")
+	}
+
+	_, err := p.ecs.UnassociateEipAddress(request)
+
+	if err == nil {
+		address.NodeId, address.EniId = "", ""
+	}
+
+	return err
+
+}
+
+// Modify bandwidth of address in Mbps
+func (p *AlibabaEcsDriver) ModifyAddressBandwidth(address *compute.Address, bandwidth int) error {
+
+	_, err := p.ecs.ModifyEipAddressAttribute(&ecs.ModifyEipAddressAttributeRequest{
+		RegionId:     tea.String(p.rq.RegionId),
+		AllocationId: tea.String(address.Id),
+		Bandwidth:    tea.String(strconv.Itoa(bandwidth)),
+	})
+
+	if err == nil {
+		address.Bandwidth = bandwidth
+	}
+
+	return err
+
+}
+
+// Release address
+func (p *AlibabaEcsDriver) ReleaseAddress(address *compute.Address) error {
+
+	_, err := p.ecs.ReleaseEipAddress(&ecs.ReleaseEipAddressRequest{
+		RegionId:     tea.String(p.rq.RegionId),
+		AllocationId: tea.String(address.Id),
+	})
+
+	return err
+
+}
+
+// 转换弹性公网 IP 信息
+func (p *AlibabaEcsDriver) toAddress(item *ecs.DescribeEipAddressesResponseBodyEipAddressesEipAddress) *compute.Address {
+
+	bandwidth, _ := strconv.Atoi(tea.StringValue(item.Bandwidth))
+	created, _ := time.Parse(time.RFC3339, tea.StringValue(item.AllocationTime))
+
+	address := &compute.Address{
+		Id:         tea.StringValue(item.AllocationId),
+		Ip:         tea.StringValue(item.IpAddress),
+		State:      compute.AddressStateUNKNOWN,
+		Bandwidth:  bandwidth,
+		ChargeType: compute.InternetChargeBYTRAFFIC,
+		CreatedAt:  created,
+		Extra: map[string]interface{}{
+			"ChargeType":   tea.StringValue(item.ChargeType),
+			"InstanceType": tea.StringValue(item.InstanceType),
+		},
+	}
+
+	if state, ok := ecsAddressStates[tea.StringValue(item.Status)]; ok {
+		address.State = state
+	}
+
+	if tea.StringValue(item.InternetChargeType) == "PayByBandwidth" {
+		address.ChargeType = compute.InternetChargeBYBANDWIDTH
+	}
+
+	switch tea.StringValue(item.InstanceType) {
+	case "EcsInstance":
+		address.NodeId = tea.StringValue(item.InstanceId)
+	case "NetworkInterface":
+		address.EniId = tea.StringValue(item.InstanceId)
+	}
+
+	return address
+
+}
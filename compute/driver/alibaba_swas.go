@@ -1,13 +1,44 @@
 package drivers
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
 	"github.com/rehiy/cloudgo/compute"
 	"github.com/rehiy/cloudgo/provider"
 	"github.com/rehiy/cloudgo/provider/alibaba"
 
 	swas "github.com/alibabacloud-go/swas-open-20200601/client"
+	"github.com/alibabacloud-go/tea/tea"
 )
 
+// 实例状态映射
+
+var swasNodeStates = map[string]compute.NodeState{
+	"Pending":   compute.NodeStatePENDING,
+	"Starting":  compute.NodeStateSTARTING,
+	"Running":   compute.NodeStateRUNNING,
+	"Stopping":  compute.NodeStateSTOPPING,
+	"Stopped":   compute.NodeStateSTOPPED,
+	"Resetting": compute.NodeStateRECONFIGURING,
+	"Upgrading": compute.NodeStateUPDATING,
+	"Disabled":  compute.NodeStateSUSPENDED,
+}
+
+// 磁盘状态映射
+
+var swasVolumeStates = map[string]compute.StorageVolumeState{
+	"ReIniting": compute.StorageVolumeStateUPDATING,
+	"Creating":  compute.StorageVolumeStateCREATING,
+	"In_Use":    compute.StorageVolumeStateINUSE,
+	"Available": compute.StorageVolumeStateAVAILABLE,
+	"Attaching": compute.StorageVolumeStateATTACHING,
+	"Detaching": compute.StorageVolumeStateUPDATING,
+}
+
 type AlibabaSwasDriver struct {
 	client *alibaba.Client
 	swas   *swas.Client
@@ -24,48 +55,325 @@ func NewAlibabaSwasDriver(rq *provider.ReqeustParam) *AlibabaSwasDriver {
 }
 
 // List all instance
-func (p *AlibabaSwasDriver) ListNodes() ([]*compute.Node, error) {
-	return nil, nil
+func (p *AlibabaSwasDriver) ListNodes(filter *compute.NodeFilter) ([]*compute.Node, error) {
+
+	request := &swas.ListInstancesRequest{
+		RegionId: tea.String(p.rq.RegionId),
+		PageSize: tea.Int32(100),
+	}
+
+	if filter != nil {
+		if len(filter.Ids) > 0 {
+			request.InstanceIds = tea.String(p.jsonList(filter.Ids...))
+		}
+		if filter.PublicIp != "" {
+			request.PublicIpAddresses = tea.String(p.jsonList(filter.PublicIp))
+		}
+	}
+
+	var items []*compute.Node
+
+	for page := int32(1); ; page++ {
+
+		request.PageNumber = tea.Int32(page)
+		resp, err := p.swas.ListInstances(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instance := range resp.Body.Instances {
+			items = append(items, p.toNode(instance))
+		}
+
+		if len(resp.Body.Instances) < 100 || page*100 >= tea.Int32Value(resp.Body.TotalCount) {
+			break
+		}
+
+	}
+
+	if err := p.fillNodeTags(items); err != nil {
+		return nil, err
+	}
+
+	p.fillAutoRenew(items)
+
+	// 其余条件不支持服务端过滤，在本地补充过滤
+	var nodes []*compute.Node
+
+	for _, node := range items {
+		if filter.Match(node) {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes, nil
+
 }
 
 // Detail instance by Id
 func (p *AlibabaSwasDriver) DetailNode(id string) (*compute.Node, error) {
-	return nil, nil
+
+	resp, err := p.swas.ListInstances(&swas.ListInstancesRequest{
+		RegionId:    tea.String(p.rq.RegionId),
+		InstanceIds: tea.String(p.jsonList(id)),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Body.Instances) == 0 {
+		return nil, nil
+	}
+
+	node := p.toNode(resp.Body.Instances[0])
+
+	if err := p.fillNodeTags([]*compute.Node{node}); err != nil {
+		return nil, err
+	}
+
+	p.fillAutoRenew([]*compute.Node{node})
+
+	return node, nil
+
 }
 
-// Create new instance
+// Create new prepaid instance, location is not needed and user data is not supported
 func (p *AlibabaSwasDriver) CreateNode(opts *compute.NodeCreateOpts) (*compute.Node, error) {
-	return nil, nil
+
+	if opts.Size == nil || opts.Image == nil {
+		return nil, compute.InvalidParameterError
+	}
+
+	if opts.UserData != nil || opts.SpotStrategy != compute.SpotStrategyNONE {
+		return nil, compute.NotSupportedError
+	}
+
+	if opts.ChargeType != "" && opts.ChargeType != compute.ChargeTypePREPAID {
+		return nil, compute.NotSupportedError
+	}
+
+	period := opts.Period
+	if period <= 0 {
+		period = 1
+	}
+
+	resp, err := p.swas.CreateInstances(&swas.CreateInstancesRequest{
+		RegionId:   tea.String(p.rq.RegionId),
+		PlanId:     tea.String(opts.Size.Id),
+		ImageId:    tea.String(opts.Image.Id),
+		ChargeType: tea.String("PrePaid"),
+		Period:     tea.Int32(int32(period)),
+		Amount:     tea.Int32(1),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Body.InstanceIds) == 0 {
+		return nil, errors.New("no instance created")
+	}
+
+	instanceId := tea.StringValue(resp.Body.InstanceIds[0])
+
+	// 创建接口不支持名称，创建后单独设置
+	if opts.Name != "" {
+		_, err = p.swas.UpdateInstanceAttribute(&swas.UpdateInstanceAttributeRequest{
+			RegionId:     tea.String(p.rq.RegionId),
+			InstanceId:   tea.String(instanceId),
+			InstanceName: tea.String(opts.Name),
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	node, err := p.DetailNode(instanceId)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if node == nil {
+		return &compute.Node{Id: instanceId, Name: opts.Name, State: compute.NodeStatePENDING}, nil
+	}
+
+	return node, nil
+
 }
 
-// Destroy an existing instance
-func (p *AlibabaSwasDriver) DestroyNode(node *compute.Node) error {
+// Update name of instance, description and hostname are not supported
+func (p *AlibabaSwasDriver) UpdateNode(node *compute.Node, attrs *compute.NodeUpdateAttrs) error {
+
+	if attrs.Description != "" || attrs.Hostname != "" {
+		return compute.NotSupportedError
+	}
+
+	if attrs.Name == "" {
+		return nil
+	}
+
+	_, err := p.swas.UpdateInstanceAttribute(&swas.UpdateInstanceAttributeRequest{
+		RegionId:     tea.String(p.rq.RegionId),
+		InstanceId:   tea.String(node.Id),
+		InstanceName: tea.String(attrs.Name),
+	})
+
+	if err == nil {
+		node.Name = attrs.Name
+	}
+
+	return err
+
+}
+
+// Reset login password of instance
+func (p *AlibabaSwasDriver) ResetNodePassword(node *compute.Node, password string, opts *compute.PasswordResetOpts) error {
+
+	if opts == nil {
+		opts = &compute.PasswordResetOpts{}
+	}
+
+	if opts.Username != "" {
+		return compute.NotSupportedError
+	}
+
+	_, err := p.swas.UpdateInstanceAttribute(&swas.UpdateInstanceAttributeRequest{
+		RegionId:   tea.String(p.rq.RegionId),
+		InstanceId: tea.String(node.Id),
+		Password:   tea.String(password),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// 密码需重启后生效
+	if (opts.Reboot || opts.ForceStop) && node.State == compute.NodeStateRUNNING {
+		return p.RebootNode(node)
+	}
+
 	return nil
+
+}
+
+// Destroy an existing instance, not supported as SWAS instances are released on expiry
+func (p *AlibabaSwasDriver) DestroyNode(node *compute.Node) error {
+
+	return compute.NotSupportedError
+
 }
 
 // Reboot instance
 func (p *AlibabaSwasDriver) RebootNode(node *compute.Node) error {
-	return nil
+
+	_, err := p.swas.RebootInstance(&swas.RebootInstanceRequest{
+		RegionId:   tea.String(p.rq.RegionId),
+		InstanceId: tea.String(node.Id),
+	})
+
+	return err
+
 }
 
 // Start instance
 func (p *AlibabaSwasDriver) StartNode(node *compute.Node) error {
-	return nil
+
+	_, err := p.swas.StartInstance(&swas.StartInstanceRequest{
+		RegionId:   tea.String(p.rq.RegionId),
+		InstanceId: tea.String(node.Id),
+	})
+
+	return err
+
 }
 
 // Stop instance
 func (p *AlibabaSwasDriver) StopNode(node *compute.Node) error {
-	return nil
+
+	_, err := p.swas.StopInstance(&swas.StopInstanceRequest{
+		RegionId:   tea.String(p.rq.RegionId),
+		InstanceId: tea.String(node.Id),
+	})
+
+	return err
+
 }
 
 // Get the current state of instance
 func (p *AlibabaSwasDriver) GetNodeState(node *compute.Node) (compute.NodeState, error) {
-	return compute.NodeState(""), nil
+
+	detail, err := p.DetailNode(node.Id)
+
+	if err != nil || detail == nil {
+		return "", err
+	}
+
+	return detail.State, nil
+
 }
 
-// Get the Console url for instance
-func (p *AlibabaSwasDriver) GetNodeConsole(node *compute.Node) (string, error) {
-	return "", nil
+// Get console session of instance, vnc or web login with username and password
+func (p *AlibabaSwasDriver) GetNodeConsole(node *compute.Node, opts *compute.ConsoleOpts) (*compute.ConsoleSession, error) {
+
+	if opts == nil {
+		opts = &compute.ConsoleOpts{}
+	}
+
+	switch opts.Type {
+	case "", compute.ConsoleTypeVNC:
+		resp, err := p.swas.DescribeInstanceVncUrl(&swas.DescribeInstanceVncUrlRequest{
+			RegionId:   tea.String(p.rq.RegionId),
+			InstanceId: tea.String(node.Id),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// 链接有效期 15 秒，仅可使用一次
+		session := &compute.ConsoleSession{
+			Type:      compute.ConsoleTypeVNC,
+			Provider:  "alibaba_swas",
+			NodeId:    node.Id,
+			Url:       tea.StringValue(resp.Body.VncUrl),
+			ExpiredAt: time.Now().Add(15 * time.Second),
+			Extra: map[string]interface{}{
+				"IsWindows": node.Image != nil && node.Image.OSType == compute.Windows,
+			},
+		}
+		return session, nil
+
+	case compute.ConsoleTypeWEB:
+		request := &swas.LoginInstanceRequest{
+			RegionId:   tea.String(p.rq.RegionId),
+			InstanceId: tea.String(node.Id),
+		}
+		if opts.Username != "" {
+			request.Username = tea.String(opts.Username)
+		}
+		if opts.Password != "" {
+			request.Password = tea.String(opts.Password)
+		}
+
+		resp, err := p.swas.LoginInstance(request)
+		if err != nil {
+			return nil, err
+		}
+
+		session := &compute.ConsoleSession{
+			Type:     compute.ConsoleTypeWEB,
+			Provider: "alibaba_swas",
+			NodeId:   node.Id,
+			Url:      tea.StringValue(resp.Body.RedirectUrl),
+		}
+		return session, nil
+	}
+
+	return nil, compute.NotSupportedError
+
 }
 
 // Get the public IP address of instance
@@ -78,9 +386,68 @@ func (p *AlibabaSwasDriver) GetNodePrivateIp(node *compute.Node) (string, error)
 	return "", nil
 }
 
-// List all available storage volumes for instance
-func (p *AlibabaSwasDriver) ListVolumes(node *compute.Node) ([]*compute.StorageVolume, error) {
-	return nil, nil
+// List all available storage volumes for instance, tags are matched locally
+func (p *AlibabaSwasDriver) ListVolumes(node *compute.Node, filter *compute.VolumeFilter) ([]*compute.StorageVolume, error) {
+
+	request := &swas.ListDisksRequest{
+		RegionId:   tea.String(p.rq.RegionId),
+		InstanceId: tea.String(node.Id),
+		PageSize:   tea.Int32(100),
+	}
+
+	volumes := []*compute.StorageVolume{}
+	ids := []string{}
+
+	for page := int32(1); ; page++ {
+
+		request.PageNumber = tea.Int32(page)
+		resp, err := p.swas.ListDisks(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, disk := range resp.Body.Disks {
+
+			state, ok := swasVolumeStates[tea.StringValue(disk.Status)]
+			if !ok {
+				state = compute.StorageVolumeStateUNKNOWN
+			}
+
+			volumes = append(volumes, &compute.StorageVolume{
+				Id:    tea.StringValue(disk.DiskId),
+				Name:  tea.StringValue(disk.DiskName),
+				Type:  tea.StringValue(disk.Category),
+				Size:  int(tea.Int32Value(disk.Size)),
+				State: state,
+			})
+			ids = append(ids, tea.StringValue(disk.DiskId))
+
+		}
+
+		if len(resp.Body.Disks) < 100 || page*100 >= tea.Int32Value(resp.Body.TotalCount) {
+			break
+		}
+
+	}
+
+	tags, err := p.resourceTags(compute.ResourceVolume, ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*compute.StorageVolume{}
+
+	for _, volume := range volumes {
+		volume.Tags = tags[volume.Id]
+		if filter == nil || compute.MatchTags(volume.Tags, filter.Tags) {
+			result = append(result, volume)
+		}
+	}
+
+	return result, nil
+
 }
 
 // Attach volume to instance
@@ -93,9 +460,74 @@ func (p *AlibabaSwasDriver) DetachVolume(node *compute.Node, volume *compute.Sto
 	return nil
 }
 
-// List all snapshots for instance
-func (p *AlibabaSwasDriver) ListSnapshots(node *compute.Node) ([]*compute.VolumeSnapshot, error) {
-	return nil, nil
+// List all snapshots for instance, tags are matched locally
+func (p *AlibabaSwasDriver) ListSnapshots(node *compute.Node, filter *compute.SnapshotFilter) ([]*compute.VolumeSnapshot, error) {
+
+	request := &swas.ListSnapshotsRequest{
+		RegionId:   tea.String(p.rq.RegionId),
+		InstanceId: tea.String(node.Id),
+		PageSize:   tea.Int32(100),
+	}
+
+	snapshots := []*compute.VolumeSnapshot{}
+	ids := []string{}
+
+	for page := int32(1); ; page++ {
+
+		request.PageNumber = tea.Int32(page)
+		resp, err := p.swas.ListSnapshots(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range resp.Body.Snapshots {
+
+			snapshot := &compute.VolumeSnapshot{
+				Id:    tea.StringValue(item.SnapshotId),
+				Name:  tea.StringValue(item.SnapshotName),
+				State: compute.StorageVolumeStateUNKNOWN,
+			}
+
+			switch tea.StringValue(item.Status) {
+			case "Accomplished":
+				snapshot.State = compute.StorageVolumeStateAVAILABLE
+			case "Progressing":
+				snapshot.State = compute.StorageVolumeStateCREATING
+			case "Failed":
+				snapshot.State = compute.StorageVolumeStateERROR
+			}
+
+			snapshot.CreatedAt, _ = time.Parse(time.RFC3339, tea.StringValue(item.CreationTime))
+
+			snapshots = append(snapshots, snapshot)
+			ids = append(ids, snapshot.Id)
+
+		}
+
+		if len(resp.Body.Snapshots) < 100 || page*100 >= tea.Int32Value(resp.Body.TotalCount) {
+			break
+		}
+
+	}
+
+	tags, err := p.resourceTags(compute.ResourceSnapshot, ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*compute.VolumeSnapshot{}
+
+	for _, snapshot := range snapshots {
+		snapshot.Tags = tags[snapshot.Id]
+		if filter == nil || compute.MatchTags(snapshot.Tags, filter.Tags) {
+			result = append(result, snapshot)
+		}
+	}
+
+	return result, nil
+
 }
 
 // Create snapshot for instance
@@ -113,9 +545,60 @@ func (p *AlibabaSwasDriver) ApplySnapshot(node *compute.Node, snapshot *compute.
 	return nil
 }
 
-// List all available images for instance
-func (p *AlibabaSwasDriver) ListImages() ([]*compute.NodeImage, error) {
-	return nil, nil
+// List all available images for instance, only custom images carry tags
+func (p *AlibabaSwasDriver) ListImages(filter *compute.ImageFilter) ([]*compute.NodeImage, error) {
+
+	resp, err := p.swas.ListImages(&swas.ListImagesRequest{
+		RegionId: tea.String(p.rq.RegionId),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	images := []*compute.NodeImage{}
+	ids := []string{}
+
+	for _, item := range resp.Body.Images {
+
+		image := &compute.NodeImage{
+			Id:     tea.StringValue(item.ImageId),
+			Name:   tea.StringValue(item.ImageName),
+			OSType: compute.OSType(strings.ToLower(tea.StringValue(item.Platform))),
+			State:  compute.NodeImageStateACCEPTED,
+			Extra: map[string]interface{}{
+				"ImageType": tea.StringValue(item.ImageType),
+			},
+		}
+
+		images = append(images, image)
+
+		if tea.StringValue(item.ImageType) == "custom" {
+			ids = append(ids, image.Id)
+		}
+
+	}
+
+	tags, err := p.resourceTags(compute.ResourceImage, ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*compute.NodeImage{}
+
+	for _, image := range images {
+		image.Tags = tags[image.Id]
+		if image.Tags == nil {
+			image.Tags = map[string]string{}
+		}
+		if filter == nil || compute.MatchTags(image.Tags, filter.Tags) {
+			result = append(result, image)
+		}
+	}
+
+	return result, nil
+
 }
 
 // Apply Image to instance
@@ -125,7 +608,41 @@ func (p *AlibabaSwasDriver) ApplyImage(node *compute.Node, image *compute.NodeIm
 
 // List all available sizes for instance
 func (p *AlibabaSwasDriver) ListSizes() ([]*compute.NodeSize, error) {
-	return nil, nil
+
+	resp, err := p.swas.ListPlans(&swas.ListPlansRequest{
+		RegionId: tea.String(p.rq.RegionId),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := []*compute.NodeSize{}
+
+	for _, plan := range resp.Body.Plans {
+
+		// 内存单位 GB，系统盘单位 GB，公网带宽单位 Mbps，价格为月价
+		sizes = append(sizes, &compute.NodeSize{
+			Id:           tea.StringValue(plan.PlanId),
+			Name:         tea.StringValue(plan.PlanId),
+			Provider:     "alibaba_swas",
+			Region:       p.rq.RegionId,
+			Architecture: compute.X86_X64,
+			Cpu:          int(tea.Int32Value(plan.Core)),
+			Ram:          int(tea.Int32Value(plan.Memory)) * 1024,
+			Disk:         int(tea.Int32Value(plan.DiskSize)),
+			Bandwidth:    int(tea.Int32Value(plan.Bandwidth)),
+			Price:        tea.Float64Value(plan.OriginPrice),
+			Currency:     tea.StringValue(plan.Currency),
+			Extra: map[string]interface{}{
+				"Flow": tea.Int32Value(plan.Flow),
+			},
+		})
+
+	}
+
+	return sizes, nil
+
 }
 
 // Resize instance
@@ -137,3 +654,363 @@ func (p *AlibabaSwasDriver) ResizeNode(node *compute.Node, opts *compute.NodeRes
 func (p *AlibabaSwasDriver) ListLocations() ([]*compute.Location, error) {
 	return nil, nil
 }
+
+// Renew prepaid instance by months, one month by default
+func (p *AlibabaSwasDriver) RenewNode(node *compute.Node, period int) error {
+
+	if period <= 0 {
+		period = 1
+	}
+
+	_, err := p.swas.RenewInstance(&swas.RenewInstanceRequest{
+		RegionId:   tea.String(p.rq.RegionId),
+		InstanceId: tea.String(node.Id),
+		Period:     tea.Int32(int32(period)),
+	})
+
+	return err
+
+}
+
+// Enable or disable auto renewal of prepaid instance
+func (p *AlibabaSwasDriver) SetAutoRenew(node *compute.Node, enabled bool) error {
+
+	return compute.NotSupportedError
+
+}
+
+// Switch instance between prepaid and postpaid, period in months for prepaid
+func (p *AlibabaSwasDriver) ModifyChargeType(node *compute.Node, chargeType compute.ChargeType, period int) error {
+
+	return compute.NotSupportedError // 仅支持包年包月
+
+}
+
+// Modify public bandwidth in Mbps, bandwidth is bound to plan, use ResizeNode instead
+func (p *AlibabaSwasDriver) ModifyNodeBandwidth(node *compute.Node, bandwidth int, chargeType compute.InternetChargeType) error {
+
+	return compute.NotSupportedError
+
+}
+
+// Run script on instances through cloud agent
+func (p *AlibabaSwasDriver) RunCommand(nodes []*compute.Node, script string, opts *compute.CommandOpts) (*compute.CommandInvocation, error) {
+
+	if opts == nil {
+		opts = &compute.CommandOpts{}
+	}
+
+	// 每次调用仅支持单个实例，分别记录执行 Id
+	invokeIds := map[string]string{}
+	invocation := &compute.CommandInvocation{
+		Extra: map[string]interface{}{"InvokeIds": invokeIds},
+	}
+
+	for _, node := range nodes {
+		request := &swas.RunCommandRequest{
+			RegionId:       tea.String(p.rq.RegionId),
+			InstanceId:     tea.String(node.Id),
+			Type:           tea.String(aliyunCommandTypes[opts.Type]),
+			CommandContent: tea.String(script),
+		}
+
+		if opts.Timeout > 0 {
+			request.Timeout = tea.Int32(int32(opts.Timeout))
+		}
+		if opts.WorkingDir != "" {
+			request.WorkingDir = tea.String(opts.WorkingDir)
+		}
+		if opts.Username != "" {
+			request.WorkingUser = tea.String(opts.Username)
+		}
+
+		resp, err := p.swas.RunCommand(request)
+
+		if err != nil {
+			return invocation, err
+		}
+
+		invokeIds[node.Id] = tea.StringValue(resp.Body.InvokeId)
+		invocation.NodeIds = append(invocation.NodeIds, node.Id)
+	}
+
+	return invocation, nil
+
+}
+
+// Get per instance results of command invocation
+func (p *AlibabaSwasDriver) GetCommandResults(invocation *compute.CommandInvocation) ([]*compute.CommandResult, error) {
+
+	invokeIds, _ := invocation.Extra["InvokeIds"].(map[string]string)
+
+	results := []*compute.CommandResult{}
+
+	for _, nodeId := range invocation.NodeIds {
+		resp, err := p.swas.DescribeInvocationResult(&swas.DescribeInvocationResultRequest{
+			RegionId:   tea.String(p.rq.RegionId),
+			InstanceId: tea.String(nodeId),
+			InvokeId:   tea.String(invokeIds[nodeId]),
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		item := resp.Body.InvocationResult
+		output, _ := base64.StdEncoding.DecodeString(tea.StringValue(item.Output))
+
+		results = append(results, &compute.CommandResult{
+			NodeId:   nodeId,
+			Status:   aliyunCommandStatus(tea.StringValue(item.InvocationStatus)),
+			ExitCode: int(tea.Int64Value(item.ExitCode)),
+			Stdout:   string(output),
+			Stderr:   tea.StringValue(item.ErrorInfo),
+		})
+	}
+
+	return results, nil
+
+}
+
+// Add or overwrite tags of resources
+func (p *AlibabaSwasDriver) TagResources(resourceType compute.ResourceType, ids []string, tags map[string]string) error {
+
+	items := []map[string]string{}
+	for k, v := range tags {
+		items = append(items, map[string]string{"Key": k, "Value": v})
+	}
+
+	payload := map[string]any{
+		"RegionId":     p.rq.RegionId,
+		"ResourceType": p.resourceType(resourceType),
+		"ResourceId":   ids,
+		"Tag":          items,
+	}
+
+	return p.client.Request("swas", "2020-06-01", "TagResources", payload, nil)
+
+}
+
+// Remove tags from resources by key
+func (p *AlibabaSwasDriver) UntagResources(resourceType compute.ResourceType, ids []string, keys []string) error {
+
+	payload := map[string]any{
+		"RegionId":     p.rq.RegionId,
+		"ResourceType": p.resourceType(resourceType),
+		"ResourceId":   ids,
+		"TagKey":       keys,
+	}
+
+	return p.client.Request("swas", "2020-06-01", "UntagResources", payload, nil)
+
+}
+
+// 生成 JSON 数组参数
+func (p *AlibabaSwasDriver) jsonList(items ...string) string {
+
+	data, _ := json.Marshal(items)
+	return string(data)
+
+}
+
+// 转换实例信息
+func (p *AlibabaSwasDriver) toNode(instance *swas.ListInstancesResponseBodyInstances) *compute.Node {
+
+	state, ok := swasNodeStates[tea.StringValue(instance.Status)]
+	if !ok {
+		state = compute.NodeStateUNKNOWN
+	}
+
+	node := &compute.Node{
+		Id:        tea.StringValue(instance.InstanceId),
+		Name:      tea.StringValue(instance.InstanceName),
+		State:     state,
+		PublicIp:  tea.StringValue(instance.PublicIpAddress),
+		PrivateIp: tea.StringValue(instance.InnerIpAddress),
+		Size: &compute.NodeSize{
+			Id: tea.StringValue(instance.PlanId),
+		},
+		Image: &compute.NodeImage{
+			Id: tea.StringValue(instance.ImageId),
+		},
+		Location: &compute.Location{
+			Id: tea.StringValue(instance.RegionId),
+		},
+		Tags: map[string]string{},
+	}
+
+	if spec := instance.ResourceSpec; spec != nil {
+		node.Size.Cpu = int(tea.Int32Value(spec.Cpu))
+		node.Size.Ram = int(tea.Float64Value(spec.Memory) * 1024)
+		node.Size.Disk = int(tea.Int32Value(spec.DiskSize))
+		node.Size.Bandwidth = int(tea.Int32Value(spec.Bandwidth))
+		node.Bandwidth = node.Size.Bandwidth
+		node.InternetCharge = compute.InternetChargeBYPACKAGE
+	}
+
+	if image := instance.Image; image != nil {
+		node.Image.Name = tea.StringValue(image.ImageName)
+		node.Image.OSType = compute.OSType(strings.ToLower(tea.StringValue(image.OsType)))
+	}
+
+	if created, err := time.Parse(time.RFC3339, tea.StringValue(instance.CreationTime)); err == nil {
+		node.CreatedAt = created
+	}
+
+	switch tea.StringValue(instance.ChargeType) {
+	case "PrePaid":
+		node.ChargeType = compute.ChargeTypePREPAID
+		node.ExpiredAt, _ = time.Parse(time.RFC3339, tea.StringValue(instance.ExpiredTime))
+	default:
+		node.ChargeType = compute.ChargeTypeUNKNOWN
+	}
+
+	return node
+
+}
+
+// 查询资源标签，返回资源 Id 到标签的映射
+func (p *AlibabaSwasDriver) resourceTags(resourceType compute.ResourceType, ids []string) (map[string]map[string]string, error) {
+
+	tags := map[string]map[string]string{}
+	for _, id := range ids {
+		tags[id] = map[string]string{}
+	}
+
+	// 每次最多查询 50 个资源
+	for start := 0; start < len(ids); start += 50 {
+		end := start + 50
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		token := ""
+		for {
+			result := struct {
+				NextToken    string
+				TagResources []struct {
+					ResourceId string
+					TagKey     string
+					TagValue   string
+				}
+			}{}
+
+			payload := map[string]any{
+				"RegionId":     p.rq.RegionId,
+				"ResourceType": p.resourceType(resourceType),
+				"ResourceId":   ids[start:end],
+			}
+			if token != "" {
+				payload["NextToken"] = token
+			}
+
+			err := p.client.Request("swas", "2020-06-01", "ListTagResources", payload, &result)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, item := range result.TagResources {
+				if tags[item.ResourceId] != nil {
+					tags[item.ResourceId][item.TagKey] = item.TagValue
+				}
+			}
+
+			if token = result.NextToken; token == "" {
+				break
+			}
+		}
+	}
+
+	return tags, nil
+
+}
+
+// 填充实例标签
+func (p *AlibabaSwasDriver) fillNodeTags(nodes []*compute.Node) error {
+
+	ids := []string{}
+	for _, node := range nodes {
+		ids = append(ids, node.Id)
+	}
+
+	tags, err := p.resourceTags(compute.ResourceNode, ids)
+
+	if err != nil {
+		return err
+	}
+
+	for _, node := range nodes {
+		node.Tags = tags[node.Id]
+	}
+
+	return nil
+
+}
+
+// 填充自动续费状态，实例接口不返回续费信息，通过费用中心查询
+// 查询失败时（如账号无费用中心权限）不影响实例查询，自动续费保持未设置
+func (p *AlibabaSwasDriver) fillAutoRenew(nodes []*compute.Node) {
+
+	ids := []string{}
+	for _, node := range nodes {
+		if node.ChargeType == compute.ChargeTypePREPAID {
+			ids = append(ids, node.Id)
+		}
+	}
+
+	renew := map[string]bool{}
+
+	// 每次最多查询 100 个实例
+	for start := 0; start < len(ids); start += 100 {
+		end := start + 100
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		result := struct {
+			Data struct {
+				InstanceList []struct {
+					InstanceID  string
+					RenewStatus string
+				}
+			}
+		}{}
+
+		payload := map[string]any{
+			"ProductCode": "swas",
+			"Region":      p.rq.RegionId,
+			"InstanceIDs": strings.Join(ids[start:end], ","),
+			"PageSize":    100,
+		}
+
+		err := p.client.RequestEndpoint("business.aliyuncs.com", "2017-12-14", "QueryAvailableInstances", payload, &result)
+		if err != nil {
+			return
+		}
+
+		for _, item := range result.Data.InstanceList {
+			renew[item.InstanceID] = item.RenewStatus == "AutoRenewal"
+		}
+	}
+
+	for _, node := range nodes {
+		node.AutoRenew = renew[node.Id]
+	}
+
+}
+
+// 资源类型名称
+func (p *AlibabaSwasDriver) resourceType(resourceType compute.ResourceType) string {
+
+	switch resourceType {
+	case compute.ResourceVolume:
+		return "disk"
+	case compute.ResourceSnapshot:
+		return "snapshot"
+	case compute.ResourceImage:
+		return "customimage"
+	}
+
+	return "instance"
+
+}
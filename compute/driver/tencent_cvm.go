@@ -1,14 +1,42 @@
 package drivers
 
 import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/rehiy/cloudgo/compute"
 	"github.com/rehiy/cloudgo/provider"
 	"github.com/rehiy/cloudgo/provider/tencent"
 
 	cbs "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cbs/v20170312"
+	tc "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
 	cvm "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cvm/v20170312"
 )
 
+// DescribeInstances 单个条件最多 5 个值，最多 10 个条件，InstanceIds 最多 100 个
+
+const (
+	tencentMaxFilterValues = 5
+	tencentMaxFilters      = 10
+	tencentMaxInstanceIds  = 100
+)
+
+// 实例状态映射
+
+var cvmNodeStates = map[string]compute.NodeState{
+	"PENDING":       compute.NodeStatePENDING,
+	"LAUNCH_FAILED": compute.NodeStateERROR,
+	"RUNNING":       compute.NodeStateRUNNING,
+	"STOPPED":       compute.NodeStateSTOPPED,
+	"STARTING":      compute.NodeStateSTARTING,
+	"STOPPING":      compute.NodeStateSTOPPING,
+	"REBOOTING":     compute.NodeStateREBOOTING,
+	"SHUTDOWN":      compute.NodeStateSUSPENDED,
+	"TERMINATING":   compute.NodeStateTERMINATED,
+}
+
 type TencentCvmDriver struct {
 	client *tencent.Client
 	cbs    *cbs.Client
@@ -27,43 +55,81 @@ func NewTencentCvmDriver(rq *provider.ReqeustParam) *TencentCvmDriver {
 }
 
 // List all instance
-func (p *TencentCvmDriver) ListNodes() ([]*compute.Node, error) {
+func (p *TencentCvmDriver) ListNodes(filter *compute.NodeFilter) ([]*compute.Node, error) {
 
-	resp, err := p.cvm.DescribeInstances(&cvm.DescribeInstancesRequest{})
+	filters := []*cvm.Filter{}
+	ids := []string{}
 
-	if err != nil {
-		return nil, err
+	if filter != nil {
+		ids = filter.Ids
+		if filter.Name != "" {
+			filters = append(filters, p.filter("instance-name", filter.Name))
+		} else if filter.NamePrefix != "" {
+			filters = append(filters, p.filter("instance-name", filter.NamePrefix))
+		}
+		if len(filter.States) > 0 {
+			states := []string{}
+			for status, state := range cvmNodeStates {
+				for _, want := range filter.States {
+					if state == want {
+						states = append(states, status)
+					}
+				}
+			}
+			if len(states) > 0 && len(states) <= tencentMaxFilterValues {
+				filters = append(filters, p.filter("instance-state", states...))
+			}
+		}
+		if filter.Zone != "" {
+			filters = append(filters, p.filter("zone", filter.Zone))
+		}
+		if filter.VpcId != "" {
+			filters = append(filters, p.filter("vpc-id", filter.VpcId))
+		}
+		if filter.PrivateIp != "" {
+			filters = append(filters, p.filter("private-ip-address", filter.PrivateIp))
+		}
+		if filter.PublicIp != "" {
+			filters = append(filters, p.filter("public-ip-address", filter.PublicIp))
+		}
+		for k, v := range filter.Tags {
+			filters = append(filters, p.tagFilter(k, v))
+		}
 	}
 
-	var nodes []*compute.Node
-
-	for _, instance := range resp.Response.InstanceSet {
-
-		// TODO: fix mapping
-		state := compute.NodeState(*instance.InstanceState)
-		osType := compute.OSType(*instance.OsName)
+	// 超出条件数量上限的部分不下推，由本地过滤补充
+	limit := tencentMaxFilters
+	if len(ids) > 0 {
+		limit--
+	}
+	if len(filters) > limit {
+		filters = filters[:limit]
+	}
 
-		node := &compute.Node{
-			Id:        *instance.InstanceId,
-			Name:      *instance.InstanceName,
-			State:     state,
-			PublicIp:  *instance.PublicIpAddresses[0],
-			PrivateIp: *instance.PrivateIpAddresses[0],
-			Size: &compute.NodeSize{
-				Id: *instance.InstanceType,
-			},
-			Image: &compute.NodeImage{
-				Id:     *instance.ImageId,
-				Name:   *instance.OsName,
-				OSType: osType,
-			},
-			Location: &compute.Location{
-				Id: *instance.Placement.Zone,
-			},
+	// InstanceIds 与 Filters 不能同时使用，有其他条件时按 instance-id 分批过滤
+	requests := []*cvm.DescribeInstancesRequest{}
+	switch {
+	case len(ids) == 0:
+		requests = append(requests, &cvm.DescribeInstancesRequest{Filters: filters})
+	case len(filters) == 0:
+		for _, batch := range tencentBatches(ids, tencentMaxInstanceIds) {
+			requests = append(requests, &cvm.DescribeInstancesRequest{InstanceIds: tc.StringPtrs(batch)})
+		}
+	default:
+		for _, batch := range tencentBatches(ids, tencentMaxFilterValues) {
+			items := append([]*cvm.Filter{p.filter("instance-id", batch...)}, filters...)
+			requests = append(requests, &cvm.DescribeInstancesRequest{Filters: items})
 		}
+	}
 
-		nodes = append(nodes, node)
+	var nodes []*compute.Node
 
+	for _, request := range requests {
+		list, err := p.describeNodes(request, filter)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, list...)
 	}
 
 	return nodes, nil
@@ -85,68 +151,85 @@ func (p *TencentCvmDriver) DetailNode(id string) (*compute.Node, error) {
 		return nil, nil
 	}
 
-	instance := resp.Response.InstanceSet[0]
+	return p.toNode(resp.Response.InstanceSet[0]), nil
 
-	// TODO: fix mapping
-	state := compute.NodeState(*instance.InstanceState)
-	osType := compute.OSType(*instance.OsName)
+}
 
-	node := &compute.Node{
-		Id:        *instance.InstanceId,
-		Name:      *instance.InstanceName,
-		State:     state,
-		PublicIp:  *instance.PublicIpAddresses[0],
-		PrivateIp: *instance.PrivateIpAddresses[0],
-		Size: &compute.NodeSize{
-			Id: *instance.InstanceType,
-		},
-		Image: &compute.NodeImage{
-			Id:     *instance.ImageId,
-			Name:   *instance.OsName,
-			OSType: osType,
-		},
-		Location: &compute.Location{
-			Id: *instance.Placement.Zone,
+// Create new instance
+func (p *TencentCvmDriver) CreateNode(opts *compute.NodeCreateOpts) (*compute.Node, error) {
+
+	if opts.Size == nil || opts.Image == nil || opts.Location == nil {
+		return nil, compute.InvalidParameterError
+	}
+
+	// TODO: login, security group, disk and vpc settings
+	request := &cvm.RunInstancesRequest{
+		InstanceType: &opts.Size.Id,
+		ImageId:      &opts.Image.Id,
+		InstanceName: &opts.Name,
+		Placement: &cvm.Placement{
+			Zone: &opts.Location.Id,
 		},
 	}
 
-	return node, nil
+	// 自定义数据 Base64 后最大 16 KB
+	if opts.UserData != nil {
+		userData, err := opts.UserData.Encode(16 * 1024)
+		if err != nil {
+			return nil, err
+		}
+		request.UserData = &userData
+	}
 
-}
+	switch opts.ChargeType {
+	case "", compute.ChargeTypePOSTPAID:
+	case compute.ChargeTypePREPAID:
+		period := opts.Period
+		if period <= 0 {
+			period = 1
+		}
+		request.InstanceChargeType = tc.StringPtr("PREPAID")
+		request.InstanceChargePrepaid = &cvm.InstanceChargePrepaid{
+			Period: tc.Int64Ptr(int64(period)),
+		}
+	default:
+		return nil, compute.NotSupportedError
+	}
 
-// Create new instance
-func (p *TencentCvmDriver) CreateNode(opts *compute.NodeCreateOpts) (*compute.Node, error) {
+	// 竞价实例必须指定最高出价，且为按量计费
+	if opts.SpotStrategy != compute.SpotStrategyNONE {
+		if opts.ChargeType == compute.ChargeTypePREPAID {
+			return nil, compute.InvalidParameterError
+		}
+		if opts.SpotPriceLimit <= 0 {
+			return nil, compute.InvalidParameterError
+		}
+		request.InstanceChargeType = tc.StringPtr("SPOTPAID")
+		request.InstanceMarketOptions = &cvm.InstanceMarketOptionsRequest{
+			MarketType: tc.StringPtr("spot"),
+			SpotOptions: &cvm.SpotMarketOptions{
+				MaxPrice:         tc.StringPtr(strconv.FormatFloat(opts.SpotPriceLimit, 'f', -1, 64)),
+				SpotInstanceType: tc.StringPtr("one-time"),
+			},
+		}
+	}
 
-	// TODO: create instance
-	resp, err := p.cvm.RunInstances(&cvm.RunInstancesRequest{
-		// InstanceChargeType: &opts.InstanceChargeType,
-		// InstanceType:       &opts.InstanceType,
-		// Placement: &cvm.Placement{
-		// 	Zone: &opts.Zone,
-		// },
-		// ImageId:       &opts.ImageId,
-		// InstanceCount: &opts.InstanceCount,
-		// InstanceName:  &opts.InstanceName,
-		// LoginSettings: &cvm.LoginSettings{
-		// 	Password: &opts.Password,
-		// },
-		// SecurityGroupIds: []*string{&opts.SecurityGroupId},
-		// SystemDisk: &cvm.SystemDisk{
-		// 	DiskType: &opts.DiskType,
-		// 	DiskSize: &opts.DiskSize,
-		// },
-		// VirtualPrivateCloud: &cvm.VirtualPrivateCloud{
-		// 	VpcId:    &opts.VpcId,
-		// 	SubnetId: &opts.SubnetId,
-		// },
-	})
+	if len(opts.Tags) > 0 {
+		spec := &cvm.TagSpecification{ResourceType: tc.StringPtr("instance")}
+		for k, v := range opts.Tags {
+			spec.Tags = append(spec.Tags, &cvm.Tag{Key: tc.StringPtr(k), Value: tc.StringPtr(v)})
+		}
+		request.TagSpecification = []*cvm.TagSpecification{spec}
+	}
+
+	resp, err := p.cvm.RunInstances(request)
 
 	if err != nil {
 		return nil, err
 	}
 
 	if len(resp.Response.InstanceIdSet) == 0 {
-		return nil, nil
+		return nil, errors.New("no instance created")
 	}
 
 	instanceId := resp.Response.InstanceIdSet[0]
@@ -157,10 +240,72 @@ func (p *TencentCvmDriver) CreateNode(opts *compute.NodeCreateOpts) (*compute.No
 		return nil, err
 	}
 
+	if node == nil {
+		return &compute.Node{Id: *instanceId, Name: opts.Name, State: compute.NodeStatePENDING}, nil
+	}
+
 	return node, nil
 
 }
 
+// Update name, description or hostname of instance
+func (p *TencentCvmDriver) UpdateNode(node *compute.Node, attrs *compute.NodeUpdateAttrs) error {
+
+	if attrs.Description != "" {
+		return compute.NotSupportedError
+	}
+
+	request := &cvm.ModifyInstancesAttributeRequest{
+		InstanceIds: []*string{&node.Id},
+	}
+
+	if attrs.Name != "" {
+		request.InstanceName = tc.StringPtr(attrs.Name)
+	}
+	if attrs.Hostname != "" {
+		request.HostName = tc.StringPtr(attrs.Hostname)
+	}
+
+	if _, err := p.cvm.ModifyInstancesAttribute(request); err != nil {
+		return err
+	}
+
+	if attrs.Name != "" {
+		node.Name = attrs.Name
+	}
+
+	// 主机名需重启后生效
+	if attrs.Hostname != "" && attrs.Reboot && node.State == compute.NodeStateRUNNING {
+		return p.RebootNode(node)
+	}
+
+	return nil
+
+}
+
+// Reset login password of instance, running instance is stopped and started by provider with ForceStop
+func (p *TencentCvmDriver) ResetNodePassword(node *compute.Node, password string, opts *compute.PasswordResetOpts) error {
+
+	if opts == nil {
+		opts = &compute.PasswordResetOpts{}
+	}
+
+	request := &cvm.ResetInstancesPasswordRequest{
+		InstanceIds: []*string{&node.Id},
+		Password:    tc.StringPtr(password),
+		ForceStop:   tc.BoolPtr(opts.ForceStop || opts.Reboot),
+	}
+
+	if opts.Username != "" {
+		request.UserName = tc.StringPtr(opts.Username)
+	}
+
+	_, err := p.cvm.ResetInstancesPassword(request)
+
+	return err
+
+}
+
 // Destroy an existing instance
 func (p *TencentCvmDriver) DestroyNode(node *compute.Node) error {
 
@@ -222,25 +367,40 @@ func (p *TencentCvmDriver) GetNodeState(node *compute.Node) (compute.NodeState,
 
 	instanceStatus := resp.Response.InstanceStatusSet[0]
 
-	// TODO: fix mapping
-	state := compute.NodeState(*instanceStatus.InstanceState)
+	state, ok := cvmNodeStates[*instanceStatus.InstanceState]
+	if !ok {
+		state = compute.NodeStateUNKNOWN
+	}
 
 	return state, nil
 
 }
 
-// Get the Console url for instance
-func (p *TencentCvmDriver) GetNodeConsole(node *compute.Node) (string, error) {
+// Get console session of instance, only vnc is supported
+func (p *TencentCvmDriver) GetNodeConsole(node *compute.Node, opts *compute.ConsoleOpts) (*compute.ConsoleSession, error) {
+
+	if opts != nil && opts.Type != "" && opts.Type != compute.ConsoleTypeVNC {
+		return nil, compute.NotSupportedError
+	}
 
 	resp, err := p.cvm.DescribeInstanceVncUrl(&cvm.DescribeInstanceVncUrlRequest{
 		InstanceId: &node.Id,
 	})
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return *resp.Response.InstanceVncUrl, nil
+	// 链接有效期 15 秒，仅可使用一次
+	session := &compute.ConsoleSession{
+		Type:      compute.ConsoleTypeVNC,
+		Provider:  "tencent_cvm",
+		NodeId:    node.Id,
+		Url:       *resp.Response.InstanceVncUrl,
+		ExpiredAt: time.Now().Add(15 * time.Second),
+	}
+
+	return session, nil
 
 }
 
@@ -289,32 +449,49 @@ func (p *TencentCvmDriver) GetNodePrivateIp(node *compute.Node) (string, error)
 }
 
 // List all available storage volumes for instance
-func (p *TencentCvmDriver) ListVolumes(node *compute.Node) ([]*compute.StorageVolume, error) {
+func (p *TencentCvmDriver) ListVolumes(node *compute.Node, filter *compute.VolumeFilter) ([]*compute.StorageVolume, error) {
 
-	resp, err := p.cvm.DescribeInstances(&cvm.DescribeInstancesRequest{
-		InstanceIds: []*string{&node.Id},
-	})
+	filterName := "instance-id"
+
+	request := &cbs.DescribeDisksRequest{
+		Filters: []*cbs.Filter{
+			{
+				Name:   &filterName,
+				Values: []*string{&node.Id},
+			},
+		},
+	}
+
+	if filter != nil {
+		for k, v := range filter.Tags {
+			tag := p.tagFilter(k, v)
+			request.Filters = append(request.Filters, &cbs.Filter{
+				Name: tag.Name, Values: tag.Values,
+			})
+		}
+	}
+
+	resp, err := p.cbs.DescribeDisks(request)
 
 	if err != nil {
 		return nil, err
 	}
 
-	instance := resp.Response.InstanceSet[0]
+	volumes := []*compute.StorageVolume{}
 
-	volumes := []*compute.StorageVolume{
-		{
-			Id:   *instance.SystemDisk.DiskId,
-			Type: *instance.SystemDisk.DiskType,
-			Size: int(*instance.SystemDisk.DiskSize),
-		},
-	}
+	for _, disk := range resp.Response.DiskSet {
 
-	for _, disk := range instance.DataDisks {
+		tags := map[string]string{}
+		for _, tag := range disk.Tags {
+			tags[*tag.Key] = *tag.Value
+		}
 
 		volumes = append(volumes, &compute.StorageVolume{
 			Id:   *disk.DiskId,
+			Name: *disk.DiskName,
 			Type: *disk.DiskType,
 			Size: int(*disk.DiskSize),
+			Tags: tags,
 		})
 
 	}
@@ -348,9 +525,9 @@ func (p *TencentCvmDriver) DetachVolume(node *compute.Node, volume *compute.Stor
 }
 
 // List all snapshots for instance
-func (p *TencentCvmDriver) ListSnapshots(node *compute.Node) ([]*compute.VolumeSnapshot, error) {
+func (p *TencentCvmDriver) ListSnapshots(node *compute.Node, filter *compute.SnapshotFilter) ([]*compute.VolumeSnapshot, error) {
 
-	volumes, err := p.ListVolumes(node)
+	volumes, err := p.ListVolumes(node, nil)
 
 	if err != nil {
 		return nil, err
@@ -363,14 +540,25 @@ func (p *TencentCvmDriver) ListSnapshots(node *compute.Node) ([]*compute.VolumeS
 
 	filterName := "disk-id"
 
-	resp, err := p.cbs.DescribeSnapshots(&cbs.DescribeSnapshotsRequest{
+	request := &cbs.DescribeSnapshotsRequest{
 		Filters: []*cbs.Filter{
 			{
 				Name:   &filterName,
 				Values: volumeIds,
 			},
 		},
-	})
+	}
+
+	if filter != nil {
+		for k, v := range filter.Tags {
+			tag := p.tagFilter(k, v)
+			request.Filters = append(request.Filters, &cbs.Filter{
+				Name: tag.Name, Values: tag.Values,
+			})
+		}
+	}
+
+	resp, err := p.cbs.DescribeSnapshots(request)
 
 	if err != nil {
 		return nil, err
@@ -380,10 +568,19 @@ func (p *TencentCvmDriver) ListSnapshots(node *compute.Node) ([]*compute.VolumeS
 
 	for _, snapshot := range resp.Response.SnapshotSet {
 
+		tags := map[string]string{}
+		for _, tag := range snapshot.Tags {
+			tags[*tag.Key] = *tag.Value
+		}
+
+		created, _ := time.ParseInLocation("2006-01-02 15:04:05", *snapshot.CreateTime, time.Local)
+
 		snapshots = append(snapshots, &compute.VolumeSnapshot{
-			Id:   *snapshot.SnapshotId,
-			Name: *snapshot.SnapshotName,
-			Size: int(*snapshot.DiskSize),
+			Id:        *snapshot.SnapshotId,
+			Name:      *snapshot.SnapshotName,
+			Size:      int(*snapshot.DiskSize),
+			CreatedAt: created,
+			Tags:      tags,
 		})
 
 	}
@@ -436,11 +633,17 @@ func (p *TencentCvmDriver) ApplySnapshot(node *compute.Node, snapshot *compute.V
 }
 
 // List all available images for instance
-func (p *TencentCvmDriver) ListImages() ([]*compute.NodeImage, error) {
+func (p *TencentCvmDriver) ListImages(filter *compute.ImageFilter) ([]*compute.NodeImage, error) {
 
-	resp, err := p.cvm.DescribeImages(&cvm.DescribeImagesRequest{
-		ImageIds: []*string{},
-	})
+	request := &cvm.DescribeImagesRequest{}
+
+	if filter != nil {
+		for k, v := range filter.Tags {
+			request.Filters = append(request.Filters, p.tagFilter(k, v))
+		}
+	}
+
+	resp, err := p.cvm.DescribeImages(request)
 
 	if err != nil {
 		return nil, err
@@ -450,9 +653,15 @@ func (p *TencentCvmDriver) ListImages() ([]*compute.NodeImage, error) {
 
 	for _, image := range resp.Response.ImageSet {
 
+		tags := map[string]string{}
+		for _, tag := range image.Tags {
+			tags[*tag.Key] = *tag.Value
+		}
+
 		images = append(images, &compute.NodeImage{
 			Id:   *image.ImageId,
 			Name: *image.ImageName,
+			Tags: tags,
 			// Description: *image.ImageDescription,
 			// Os:          *image.OsName,
 			// OsVersion:   *image.OsVersion,
@@ -489,18 +698,45 @@ func (p *TencentCvmDriver) ListSizes() ([]*compute.NodeSize, error) {
 
 	for _, instanceType := range resp.Response.InstanceTypeQuotaSet {
 
-		disk := instanceType.LocalDiskTypeList[0].MinSize
-		arch := compute.Architecture(*instanceType.InstanceFamily)
+		// 仅统计机型必选的本地盘，单位 GiB
+		disk := int64(0)
+		for _, local := range instanceType.LocalDiskTypeList {
+			if local.Required != nil && *local.Required == "REQUIRED" && local.MinSize != nil {
+				disk += *local.MinSize
+			}
+		}
 
-		sizes = append(sizes, &compute.NodeSize{
+		// 内存单位 GB，内网带宽单位 Gbps，公网带宽在购买时选择
+		size := &compute.NodeSize{
 			Id:           *instanceType.InstanceType,
 			Name:         *instanceType.InstanceType,
-			Architecture: arch,
+			Provider:     "tencent_cvm",
+			Region:       p.rq.RegionId,
+			Architecture: p.architecture(instanceType.CpuType),
 			Gpu:          int(*instanceType.Gpu),
 			Cpu:          int(*instanceType.Cpu),
-			Ram:          int(*instanceType.Memory),
-			Disk:         int(*disk),
-		})
+			Ram:          int(*instanceType.Memory) * 1024,
+			Disk:         int(disk),
+			Extra:        map[string]interface{}{},
+		}
+
+		if instanceType.InstanceBandwidth != nil {
+			size.Extra["InstanceBandwidth"] = int(*instanceType.InstanceBandwidth * 1000)
+		}
+
+		// 按量计费单价单位 元/小时，包年包月原价单位 元/月
+		if price := instanceType.Price; price != nil {
+			if price.UnitPrice != nil && price.ChargeUnit != nil && *price.ChargeUnit == "HOUR" {
+				size.Price = *price.UnitPrice * compute.HoursPerMonth
+			} else if price.OriginalPrice != nil {
+				size.Price = *price.OriginalPrice
+			}
+			if size.Price > 0 {
+				size.Currency = "CNY"
+			}
+		}
+
+		sizes = append(sizes, size)
 
 	}
 
@@ -544,3 +780,377 @@ func (p *TencentCvmDriver) ListLocations() ([]*compute.Location, error) {
 	return locations, nil
 
 }
+
+// Renew prepaid instance by months, one month by default
+func (p *TencentCvmDriver) RenewNode(node *compute.Node, period int) error {
+
+	if period <= 0 {
+		period = 1
+	}
+
+	_, err := p.cvm.RenewInstances(&cvm.RenewInstancesRequest{
+		InstanceIds: []*string{&node.Id},
+		InstanceChargePrepaid: &cvm.InstanceChargePrepaid{
+			Period: tc.Int64Ptr(int64(period)),
+		},
+	})
+
+	return err
+
+}
+
+// Enable or disable auto renewal of prepaid instance
+func (p *TencentCvmDriver) SetAutoRenew(node *compute.Node, enabled bool) error {
+
+	flag := "NOTIFY_AND_MANUAL_RENEW"
+	if enabled {
+		flag = "NOTIFY_AND_AUTO_RENEW"
+	}
+
+	_, err := p.cvm.ModifyInstancesRenewFlag(&cvm.ModifyInstancesRenewFlagRequest{
+		InstanceIds: []*string{&node.Id},
+		RenewFlag:   &flag,
+	})
+
+	return err
+
+}
+
+// Switch instance between prepaid and postpaid, period in months for prepaid, one month by default
+func (p *TencentCvmDriver) ModifyChargeType(node *compute.Node, chargeType compute.ChargeType, period int) error {
+
+	if period <= 0 {
+		period = 1
+	}
+
+	request := &cvm.ModifyInstancesChargeTypeRequest{
+		InstanceIds: []*string{&node.Id},
+	}
+
+	switch chargeType {
+	case compute.ChargeTypePREPAID:
+		request.InstanceChargeType = tc.StringPtr("PREPAID")
+		request.InstanceChargePrepaid = &cvm.InstanceChargePrepaid{
+			Period: tc.Int64Ptr(int64(period)),
+		}
+	case compute.ChargeTypePOSTPAID:
+		request.InstanceChargeType = tc.StringPtr("POSTPAID_BY_HOUR")
+	default:
+		return compute.NotSupportedError
+	}
+
+	_, err := p.cvm.ModifyInstancesChargeType(request)
+
+	return err
+
+}
+
+// Modify public bandwidth in Mbps, changing the internet charge type is not supported
+func (p *TencentCvmDriver) ModifyNodeBandwidth(node *compute.Node, bandwidth int, chargeType compute.InternetChargeType) error {
+
+	if chargeType != "" && chargeType != node.InternetCharge {
+		return compute.NotSupportedError
+	}
+
+	_, err := p.cvm.ResetInstancesInternetMaxBandwidth(&cvm.ResetInstancesInternetMaxBandwidthRequest{
+		InstanceIds: []*string{&node.Id},
+		InternetAccessible: &cvm.InternetAccessible{
+			InternetMaxBandwidthOut: tc.Int64Ptr(int64(bandwidth)),
+		},
+	})
+
+	if err == nil {
+		node.Bandwidth = bandwidth
+	}
+
+	return err
+
+}
+
+// Run script on instances through cloud agent
+func (p *TencentCvmDriver) RunCommand(nodes []*compute.Node, script string, opts *compute.CommandOpts) (*compute.CommandInvocation, error) {
+
+	return tatRunCommand(p.client, nodes, script, opts)
+
+}
+
+// Get per instance results of command invocation
+func (p *TencentCvmDriver) GetCommandResults(invocation *compute.CommandInvocation) ([]*compute.CommandResult, error) {
+
+	return tatCommandResults(p.client, invocation)
+
+}
+
+// Add or overwrite tags of resources
+func (p *TencentCvmDriver) TagResources(resourceType compute.ResourceType, ids []string, tags map[string]string) error {
+
+	resources, err := p.client.ResourceNames("cvm", p.resourcePrefix(resourceType), ids)
+
+	if err != nil {
+		return err
+	}
+
+	return p.client.TagResources(resources, tags)
+
+}
+
+// Remove tags from resources by key
+func (p *TencentCvmDriver) UntagResources(resourceType compute.ResourceType, ids []string, keys []string) error {
+
+	resources, err := p.client.ResourceNames("cvm", p.resourcePrefix(resourceType), ids)
+
+	if err != nil {
+		return err
+	}
+
+	return p.client.UntagResources(resources, keys)
+
+}
+
+// Query current spot prices of size, empty location means all zones
+func (p *TencentCvmDriver) ListSpotPrices(size *compute.NodeSize, location *compute.Location) ([]*compute.SpotPrice, error) {
+
+	filters := []*cvm.Filter{
+		p.filter("instance-type", size.Id),
+		p.filter("instance-charge-type", "SPOTPAID"),
+	}
+
+	if location != nil && location.Id != "" {
+		filters = append(filters, p.filter("zone", location.Id))
+	}
+
+	resp, err := p.cvm.DescribeZoneInstanceConfigInfos(&cvm.DescribeZoneInstanceConfigInfosRequest{
+		Filters: filters,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	prices := []*compute.SpotPrice{}
+	now := time.Now()
+
+	for _, item := range resp.Response.InstanceTypeQuotaSet {
+		if item.Price == nil {
+			continue
+		}
+		price := &compute.SpotPrice{
+			SizeId:    *item.InstanceType,
+			Zone:      *item.Zone,
+			Timestamp: now,
+		}
+		if item.Price.UnitPriceDiscount != nil {
+			price.Price = *item.Price.UnitPriceDiscount
+		}
+		if item.Price.UnitPrice != nil {
+			price.OriginPrice = *item.Price.UnitPrice
+		}
+		prices = append(prices, price)
+	}
+
+	return prices, nil
+
+}
+
+// List pending interruption notices of spot instances, notices are only delivered by event bus
+func (p *TencentCvmDriver) ListSpotInterruptions(nodes []*compute.Node) ([]*compute.SpotInterruption, error) {
+
+	return nil, compute.NotSupportedError
+
+}
+
+// 资源六段式前缀
+func (p *TencentCvmDriver) resourcePrefix(resourceType compute.ResourceType) string {
+
+	switch resourceType {
+	case compute.ResourceVolume:
+		return "volume"
+	case compute.ResourceSnapshot:
+		return "snapshot"
+	case compute.ResourceImage:
+		return "image"
+	}
+
+	return "instance"
+
+}
+
+// 分页查询实例，镜像等条件不支持服务端过滤，在本地补充过滤
+func (p *TencentCvmDriver) describeNodes(request *cvm.DescribeInstancesRequest, filter *compute.NodeFilter) ([]*compute.Node, error) {
+
+	limit := int64(100)
+	request.Limit = &limit
+
+	var nodes []*compute.Node
+
+	for offset := int64(0); ; offset += limit {
+
+		request.Offset = &offset
+		resp, err := p.cvm.DescribeInstances(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instance := range resp.Response.InstanceSet {
+			if node := p.toNode(instance); filter.Match(node) {
+				nodes = append(nodes, node)
+			}
+		}
+
+		if int64(len(resp.Response.InstanceSet)) < limit || offset+limit >= *resp.Response.TotalCount {
+			break
+		}
+
+	}
+
+	return nodes, nil
+
+}
+
+// 过滤条件
+func (p *TencentCvmDriver) filter(name string, values ...string) *cvm.Filter {
+
+	items := []*string{}
+	for _, v := range values {
+		items = append(items, tc.StringPtr(v))
+	}
+
+	return &cvm.Filter{Name: &name, Values: items}
+
+}
+
+// 标签过滤条件，值为空时仅匹配标签键
+func (p *TencentCvmDriver) tagFilter(key, value string) *cvm.Filter {
+
+	if value == "" {
+		name := "tag-key"
+		return &cvm.Filter{Name: &name, Values: []*string{&key}}
+	}
+
+	name := "tag:" + key
+	return &cvm.Filter{Name: &name, Values: []*string{&value}}
+
+}
+
+// 按数量拆分列表
+func tencentBatches(items []string, size int) [][]string {
+
+	batches := [][]string{}
+	for len(items) > size {
+		batches = append(batches, items[:size])
+		items = items[size:]
+	}
+
+	return append(batches, items)
+
+}
+
+// 转换实例信息
+func (p *TencentCvmDriver) toNode(instance *cvm.Instance) *compute.Node {
+
+	state, ok := cvmNodeStates[*instance.InstanceState]
+	if !ok {
+		state = compute.NodeStateUNKNOWN
+	}
+
+	// TODO: fix mapping
+	osType := compute.OSType(*instance.OsName)
+
+	node := &compute.Node{
+		Id:    *instance.InstanceId,
+		Name:  *instance.InstanceName,
+		State: state,
+		Size: &compute.NodeSize{
+			Id:  *instance.InstanceType,
+			Cpu: int(*instance.CPU),
+			Ram: int(*instance.Memory) * 1024,
+		},
+		Image: &compute.NodeImage{
+			Id:     *instance.ImageId,
+			Name:   *instance.OsName,
+			OSType: osType,
+		},
+		Location: &compute.Location{
+			Id: *instance.Placement.Zone,
+		},
+		Tags: map[string]string{},
+	}
+
+	if len(instance.PublicIpAddresses) > 0 {
+		node.PublicIp = *instance.PublicIpAddresses[0]
+	}
+
+	if len(instance.PrivateIpAddresses) > 0 {
+		node.PrivateIp = *instance.PrivateIpAddresses[0]
+	}
+
+	if instance.VirtualPrivateCloud != nil && instance.VirtualPrivateCloud.VpcId != nil {
+		node.VpcId = *instance.VirtualPrivateCloud.VpcId
+	}
+
+	for _, tag := range instance.Tags {
+		node.Tags[*tag.Key] = *tag.Value
+	}
+
+	if instance.CreatedTime != nil {
+		node.CreatedAt, _ = time.Parse(time.RFC3339, *instance.CreatedTime)
+	}
+
+	if instance.ExpiredTime != nil {
+		node.ExpiredAt, _ = time.Parse(time.RFC3339, *instance.ExpiredTime)
+	}
+
+	if instance.RenewFlag != nil {
+		node.AutoRenew = *instance.RenewFlag == "NOTIFY_AND_AUTO_RENEW"
+	}
+
+	node.InternetCharge = compute.InternetChargeUNKNOWN
+
+	if net := instance.InternetAccessible; net != nil {
+		if net.InternetMaxBandwidthOut != nil {
+			node.Bandwidth = int(*net.InternetMaxBandwidthOut)
+		}
+		if net.InternetChargeType != nil {
+			node.InternetCharge = tencentInternetChargeType(*net.InternetChargeType)
+		}
+	}
+
+	chargeType := ""
+	if instance.InstanceChargeType != nil {
+		chargeType = *instance.InstanceChargeType
+	}
+
+	switch chargeType {
+	case "PREPAID":
+		node.ChargeType = compute.ChargeTypePREPAID
+	case "POSTPAID_BY_HOUR":
+		node.ChargeType = compute.ChargeTypePOSTPAID
+	case "SPOTPAID":
+		node.ChargeType = compute.ChargeTypePOSTPAID
+		node.Spot = true
+	default:
+		node.ChargeType = compute.ChargeTypeUNKNOWN
+	}
+
+	return node
+
+}
+
+// 根据处理器型号判断架构
+func (p *TencentCvmDriver) architecture(cpuType *string) compute.Architecture {
+
+	if cpuType == nil {
+		return compute.UnknownArch
+	}
+
+	name := strings.ToLower(*cpuType)
+	for _, arm := range []string{"ampere", "kunpeng", "yitian", "arm"} {
+		if strings.Contains(name, arm) {
+			return compute.Arm64
+		}
+	}
+
+	return compute.X86_X64
+
+}
@@ -0,0 +1,299 @@
+package drivers
+
+import (
+	"time"
+
+	"github.com/rehiy/cloudgo/compute"
+)
+
+// 弹性公网 IP https://cloud.tencent.com/document/api/215/16699
+
+var vpcAddressStates = map[string]compute.AddressState{
+	"CREATING":  compute.AddressStatePENDING,
+	"BINDING":   compute.AddressStateBINDING,
+	"BIND":      compute.AddressStateINUSE,
+	"BIND_ENI":  compute.AddressStateINUSE,
+	"UNBINDING": compute.AddressStateUNBINDING,
+	"UNBIND":    compute.AddressStateAVAILABLE,
+	"OFFLINING": compute.AddressStatePENDING,
+}
+
+var tencentInternetChargeTypes = map[compute.InternetChargeType]string{
+	compute.InternetChargeBYTRAFFIC:   "TRAFFIC_POSTPAID_BY_HOUR",
+	compute.InternetChargeBYBANDWIDTH: "BANDWIDTH_POSTPAID_BY_HOUR",
+}
+
+type vpcAddress struct {
+	AddressId               string
+	AddressIp               string
+	AddressStatus           string
+	InstanceId              string
+	NetworkInterfaceId      string
+	PrivateAddressIp        string
+	InternetServiceProvider string
+	InternetChargeType      string
+	Bandwidth               int
+	CreatedTime             string
+}
+
+// List all addresses with binding status
+func (p *TencentCvmDriver) ListAddresses() ([]*compute.Address, error) {
+
+	return p.describeAddresses(nil)
+
+}
+
+// Allocate new address
+func (p *TencentCvmDriver) AllocateAddress(opts *compute.AddressCreateOpts) (*compute.Address, error) {
+
+	payload := map[string]any{
+		"AddressCount": 1,
+	}
+
+	if opts.Bandwidth > 0 {
+		payload["InternetMaxBandwidthOut"] = opts.Bandwidth
+	}
+	if opts.ChargeType != "" {
+		chargeType, ok := tencentInternetChargeTypes[opts.ChargeType]
+		if !ok {
+			return nil, compute.NotSupportedError
+		}
+		payload["InternetChargeType"] = chargeType
+	}
+	if opts.Line != "" {
+		payload["InternetServiceProvider"] = opts.Line
+	}
+
+	result := struct {
+		AddressSet []string
+	}{}
+
+	err := p.client.Request("vpc", "2017-03-12", "AllocateAddresses", payload, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.AddressSet) == 0 {
+		return nil, compute.AddressNotFoundError
+	}
+
+	address := &compute.Address{
+		Id:         result.AddressSet[0],
+		State:      compute.AddressStatePENDING,
+		Bandwidth:  opts.Bandwidth,
+		ChargeType: opts.ChargeType,
+		Line:       opts.Line,
+	}
+
+	// 分配为异步操作，尽量补全地址信息
+	if addresses, err := p.describeAddresses(result.AddressSet); err == nil && len(addresses) > 0 {
+		address = addresses[0]
+	}
+
+	return address, nil
+
+}
+
+// Bind address to instance
+func (p *TencentCvmDriver) AssociateAddress(address *compute.Address, node *compute.Node) error {
+
+	payload := map[string]any{
+		"AddressId":  address.Id,
+		"InstanceId": node.Id,
+	}
+
+	err := p.client.Request("vpc", "2017-03-12", "AssociateAddress", payload, nil)
+
+	if err == nil {
+		address.NodeId = node.Id
+	}
+
+	return err
+
+}
+
+// Bind address to elastic network interface
+func (p *TencentCvmDriver) AssociateAddressEni(address *compute.Address, eniId string) error {
+
+	// 绑定网卡时需要指定内网 IP，使用网卡主 IP
+	result := struct {
+		NetworkInterfaceSet []struct {
+			PrivateIpAddressSet []struct {
+				PrivateIpAddress string
+				Primary          bool
+			}
+		}
+	}{}
+
+	payload := map[string]any{
+		"NetworkInterfaceIds": []string{eniId},
+	}
+
+	err := p.client.Request("vpc", "2017-03-12", "DescribeNetworkInterfaces", payload, &result)
+	if err != nil {
+		return err
+	}
+
+	privateIp := ""
+	for _, eni := range result.NetworkInterfaceSet {
+		for _, ip := range eni.PrivateIpAddressSet {
+			if ip.Primary {
+				privateIp = ip.PrivateIpAddress
+			}
+		}
+	}
+
+	if privateIp == "" {
+		return compute.AddressNotFoundError
+	}
+
+	payload = map[string]any{
+		"AddressId":          address.Id,
+		"NetworkInterfaceId": eniId,
+		"PrivateIpAddress":   privateIp,
+	}
+
+	err = p.client.Request("vpc", "2017-03-12", "AssociateAddress", payload, nil)
+
+	if err == nil {
+		address.EniId = eniId
+	}
+
+	return err
+
+}
+
+// Unbind address from instance or network interface
+func (p *TencentCvmDriver) DisassociateAddress(address *compute.Address) error {
+
+	payload := map[string]any{
+		"AddressId": address.Id,
+	}
+
+	err := p.client.Request("vpc", "2017-03-12", "DisassociateAddress", payload, nil)
+
+	if err == nil {
+		address.NodeId, address.EniId = "", ""
+	}
+
+	return err
+
+}
+
+// Modify bandwidth of address in Mbps
+func (p *TencentCvmDriver) ModifyAddressBandwidth(address *compute.Address, bandwidth int) error {
+
+	payload := map[string]any{
+		"AddressIds":              []string{address.Id},
+		"InternetMaxBandwidthOut": bandwidth,
+	}
+
+	err := p.client.Request("vpc", "2017-03-12", "ModifyAddressesBandwidth", payload, nil)
+
+	if err == nil {
+		address.Bandwidth = bandwidth
+	}
+
+	return err
+
+}
+
+// Release address
+func (p *TencentCvmDriver) ReleaseAddress(address *compute.Address) error {
+
+	payload := map[string]any{
+		"AddressIds": []string{address.Id},
+	}
+
+	return p.client.Request("vpc", "2017-03-12", "ReleaseAddresses", payload, nil)
+
+}
+
+// 查询弹性公网 IP
+func (p *TencentCvmDriver) describeAddresses(ids []string) ([]*compute.Address, error) {
+
+	payload := map[string]any{
+		"Offset": 0,
+		"Limit":  100,
+	}
+
+	if len(ids) > 0 {
+		payload["AddressIds"] = ids
+	}
+
+	addresses := []*compute.Address{}
+
+	for {
+		result := struct {
+			TotalCount int
+			AddressSet []*vpcAddress
+		}{}
+
+		err := p.client.Request("vpc", "2017-03-12", "DescribeAddresses", payload, &result)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range result.AddressSet {
+			addresses = append(addresses, p.toAddress(item))
+		}
+
+		if len(addresses) >= result.TotalCount || len(result.AddressSet) == 0 {
+			break
+		}
+
+		payload["Offset"] = len(addresses)
+	}
+
+	return addresses, nil
+
+}
+
+// 转换弹性公网 IP 信息
+func (p *TencentCvmDriver) toAddress(item *vpcAddress) *compute.Address {
+
+	created, _ := time.Parse(time.RFC3339, item.CreatedTime)
+
+	address := &compute.Address{
+		Id:         item.AddressId,
+		Ip:         item.AddressIp,
+		State:      compute.AddressStateUNKNOWN,
+		Bandwidth:  item.Bandwidth,
+		ChargeType: compute.InternetChargeBYTRAFFIC,
+		Line:       item.InternetServiceProvider,
+		NodeId:     item.InstanceId,
+		EniId:      item.NetworkInterfaceId,
+		CreatedAt:  created,
+		Extra: map[string]interface{}{
+			"InternetChargeType": item.InternetChargeType,
+			"PrivateAddressIp":   item.PrivateAddressIp,
+		},
+	}
+
+	if state, ok := vpcAddressStates[item.AddressStatus]; ok {
+		address.State = state
+	}
+
+	if item.InternetChargeType == "BANDWIDTH_POSTPAID_BY_HOUR" {
+		address.ChargeType = compute.InternetChargeBYBANDWIDTH
+	}
+
+	return address
+
+}
+
+// 转换网络计费模式
+func tencentInternetChargeType(chargeType string) compute.InternetChargeType {
+
+	switch chargeType {
+	case "TRAFFIC_POSTPAID_BY_HOUR":
+		return compute.InternetChargeBYTRAFFIC
+	case "BANDWIDTH_POSTPAID_BY_HOUR", "BANDWIDTH_PREPAID":
+		return compute.InternetChargeBYBANDWIDTH
+	case "BANDWIDTH_PACKAGE", "FLOW_PACKAGE":
+		return compute.InternetChargeBYPACKAGE
+	}
+
+	return compute.InternetChargeUNKNOWN
+
+}
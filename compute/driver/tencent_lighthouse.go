@@ -1,13 +1,51 @@
 package drivers
 
 import (
+	"errors"
+	"time"
+
 	"github.com/rehiy/cloudgo/compute"
 	"github.com/rehiy/cloudgo/provider"
 	"github.com/rehiy/cloudgo/provider/tencent"
 
+	tc "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
 	lighthouse "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/lighthouse/v20200324"
 )
 
+// 实例状态映射
+
+var lighthouseNodeStates = map[string]compute.NodeState{
+	"PENDING":           compute.NodeStatePENDING,
+	"LAUNCH_FAILED":     compute.NodeStateERROR,
+	"RUNNING":           compute.NodeStateRUNNING,
+	"STOPPED":           compute.NodeStateSTOPPED,
+	"STARTING":          compute.NodeStateSTARTING,
+	"STOPPING":          compute.NodeStateSTOPPING,
+	"REBOOTING":         compute.NodeStateREBOOTING,
+	"SHUTDOWN":          compute.NodeStateSUSPENDED,
+	"TERMINATING":       compute.NodeStateTERMINATED,
+	"DELETING":          compute.NodeStateTERMINATED,
+	"FREEZING":          compute.NodeStateSUSPENDED,
+	"ENTER_RESCUE_MODE": compute.NodeStateRECONFIGURING,
+	"RESCUE_MODE":       compute.NodeStateRECONFIGURING,
+	"EXIT_RESCUE_MODE":  compute.NodeStateRECONFIGURING,
+}
+
+// 磁盘状态映射
+
+var lighthouseVolumeStates = map[string]compute.StorageVolumeState{
+	"PENDING":        compute.StorageVolumeStateCREATING,
+	"UNATTACHED":     compute.StorageVolumeStateAVAILABLE,
+	"ATTACHING":      compute.StorageVolumeStateATTACHING,
+	"ATTACHED":       compute.StorageVolumeStateINUSE,
+	"DETACHING":      compute.StorageVolumeStateUPDATING,
+	"SHUTDOWN":       compute.StorageVolumeStateUNKNOWN,
+	"CREATED_FAILED": compute.StorageVolumeStateERROR,
+	"TERMINATING":    compute.StorageVolumeStateDELETING,
+	"DELETING":       compute.StorageVolumeStateDELETING,
+	"FREEZING":       compute.StorageVolumeStateUNKNOWN,
+}
+
 type TencentLighthouseDriver struct {
 	client     *tencent.Client
 	lighthouse *lighthouse.Client
@@ -24,43 +62,78 @@ func NewTencentLighthouseDriver(rq *provider.ReqeustParam) *TencentLighthouseDri
 }
 
 // List all instance
-func (p *TencentLighthouseDriver) ListNodes() ([]*compute.Node, error) {
+func (p *TencentLighthouseDriver) ListNodes(filter *compute.NodeFilter) ([]*compute.Node, error) {
 
-	resp, err := p.lighthouse.DescribeInstances(&lighthouse.DescribeInstancesRequest{})
+	filters := []*lighthouse.Filter{}
+	ids := []string{}
 
-	if err != nil {
-		return nil, err
+	if filter != nil {
+		ids = filter.Ids
+		if filter.Name != "" {
+			filters = append(filters, p.filter("instance-name", filter.Name))
+		} else if filter.NamePrefix != "" {
+			filters = append(filters, p.filter("instance-name", filter.NamePrefix))
+		}
+		if len(filter.States) > 0 {
+			states := []string{}
+			for status, state := range lighthouseNodeStates {
+				for _, want := range filter.States {
+					if state == want {
+						states = append(states, status)
+					}
+				}
+			}
+			if len(states) > 0 && len(states) <= tencentMaxFilterValues {
+				filters = append(filters, p.filter("instance-state", states...))
+			}
+		}
+		if filter.Zone != "" {
+			filters = append(filters, p.filter("zone", filter.Zone))
+		}
+		if filter.PrivateIp != "" {
+			filters = append(filters, p.filter("private-ip-address", filter.PrivateIp))
+		}
+		if filter.PublicIp != "" {
+			filters = append(filters, p.filter("public-ip-address", filter.PublicIp))
+		}
+		for k, v := range filter.Tags {
+			filters = append(filters, p.tagFilter(k, v))
+		}
 	}
 
-	var nodes []*compute.Node
-
-	for _, instance := range resp.Response.InstanceSet {
-
-		// TODO: fix mapping
-		state := compute.NodeState(*instance.InstanceState)
-		osType := compute.OSType(*instance.OsName)
+	// 超出条件数量上限的部分不下推，由本地过滤补充
+	limit := tencentMaxFilters
+	if len(ids) > 0 {
+		limit--
+	}
+	if len(filters) > limit {
+		filters = filters[:limit]
+	}
 
-		node := &compute.Node{
-			Id:        *instance.InstanceId,
-			Name:      *instance.InstanceName,
-			State:     state,
-			PublicIp:  *instance.PublicAddresses[0],
-			PrivateIp: *instance.PrivateAddresses[0],
-			Size: &compute.NodeSize{
-				Id: *instance.BundleId,
-			},
-			Image: &compute.NodeImage{
-				Id:     "",
-				Name:   *instance.OsName,
-				OSType: osType,
-			},
-			Location: &compute.Location{
-				Id: *instance.Zone,
-			},
+	// InstanceIds 与 Filters 不能同时使用，有其他条件时按 instance-id 分批过滤
+	requests := []*lighthouse.DescribeInstancesRequest{}
+	switch {
+	case len(ids) == 0:
+		requests = append(requests, &lighthouse.DescribeInstancesRequest{Filters: filters})
+	case len(filters) == 0:
+		for _, batch := range tencentBatches(ids, tencentMaxInstanceIds) {
+			requests = append(requests, &lighthouse.DescribeInstancesRequest{InstanceIds: tc.StringPtrs(batch)})
 		}
+	default:
+		for _, batch := range tencentBatches(ids, tencentMaxFilterValues) {
+			items := append([]*lighthouse.Filter{p.filter("instance-id", batch...)}, filters...)
+			requests = append(requests, &lighthouse.DescribeInstancesRequest{Filters: items})
+		}
+	}
 
-		nodes = append(nodes, node)
+	var nodes []*compute.Node
 
+	for _, request := range requests {
+		list, err := p.describeNodes(request, filter)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, list...)
 	}
 
 	return nodes, nil
@@ -82,40 +155,39 @@ func (p *TencentLighthouseDriver) DetailNode(id string) (*compute.Node, error) {
 		return nil, nil
 	}
 
-	instance := resp.Response.InstanceSet[0]
+	return p.toNode(resp.Response.InstanceSet[0]), nil
 
-	// TODO: fix mapping
-	state := compute.NodeState(*instance.InstanceState)
-	osType := compute.OSType(*instance.OsName)
+}
 
-	node := &compute.Node{
-		Id:        *instance.InstanceId,
-		Name:      *instance.InstanceName,
-		State:     state,
-		PublicIp:  *instance.PublicAddresses[0],
-		PrivateIp: *instance.PrivateAddresses[0],
-		Size: &compute.NodeSize{
-			Id: *instance.BundleId,
-		},
-		Image: &compute.NodeImage{
-			Id:     "",
-			Name:   *instance.OsName,
-			OSType: osType,
-		},
-		Location: &compute.Location{
-			Id: *instance.Zone,
-		},
+// Create new instance
+func (p *TencentLighthouseDriver) CreateNode(opts *compute.NodeCreateOpts) (*compute.Node, error) {
+
+	if opts.Size == nil || opts.Image == nil || opts.Location == nil {
+		return nil, compute.InvalidParameterError
 	}
 
-	return node, nil
+	// 轻量应用服务器创建接口不支持自定义数据，且仅支持包年包月
+	if opts.UserData != nil || opts.SpotStrategy != compute.SpotStrategyNONE {
+		return nil, compute.NotSupportedError
+	}
 
-}
+	if opts.ChargeType != "" && opts.ChargeType != compute.ChargeTypePREPAID {
+		return nil, compute.NotSupportedError
+	}
 
-// Create new instance
-func (p *TencentLighthouseDriver) CreateNode(opts *compute.NodeCreateOpts) (*compute.Node, error) {
+	period := opts.Period
+	if period <= 0 {
+		period = 1
+	}
 
 	resp, err := p.lighthouse.CreateInstances(&lighthouse.CreateInstancesRequest{
-		// TODO: create instance
+		BundleId:     tc.StringPtr(opts.Size.Id),
+		BlueprintId:  tc.StringPtr(opts.Image.Id),
+		InstanceName: tc.StringPtr(opts.Name),
+		Zones:        []*string{tc.StringPtr(opts.Location.Id)},
+		InstanceChargePrepaid: &lighthouse.InstanceChargePrepaid{
+			Period: tc.Int64Ptr(int64(period)),
+		},
 	})
 
 	if err != nil {
@@ -123,7 +195,7 @@ func (p *TencentLighthouseDriver) CreateNode(opts *compute.NodeCreateOpts) (*com
 	}
 
 	if len(resp.Response.InstanceIdSet) == 0 {
-		return nil, nil
+		return nil, errors.New("no instance created")
 	}
 
 	instanceId := resp.Response.InstanceIdSet[0]
@@ -134,10 +206,60 @@ func (p *TencentLighthouseDriver) CreateNode(opts *compute.NodeCreateOpts) (*com
 		return nil, err
 	}
 
+	if node == nil {
+		return &compute.Node{Id: *instanceId, Name: opts.Name, State: compute.NodeStatePENDING}, nil
+	}
+
 	return node, nil
 
 }
 
+// Update name of instance, description and hostname are not supported
+func (p *TencentLighthouseDriver) UpdateNode(node *compute.Node, attrs *compute.NodeUpdateAttrs) error {
+
+	if attrs.Description != "" || attrs.Hostname != "" {
+		return compute.NotSupportedError
+	}
+
+	if attrs.Name == "" {
+		return nil
+	}
+
+	_, err := p.lighthouse.ModifyInstancesAttribute(&lighthouse.ModifyInstancesAttributeRequest{
+		InstanceIds:  []*string{&node.Id},
+		InstanceName: tc.StringPtr(attrs.Name),
+	})
+
+	if err == nil {
+		node.Name = attrs.Name
+	}
+
+	return err
+
+}
+
+// Reset login password of instance, running instance is stopped and started by provider
+func (p *TencentLighthouseDriver) ResetNodePassword(node *compute.Node, password string, opts *compute.PasswordResetOpts) error {
+
+	if opts == nil {
+		opts = &compute.PasswordResetOpts{}
+	}
+
+	request := &lighthouse.ResetInstancesPasswordRequest{
+		InstanceIds: []*string{&node.Id},
+		Password:    tc.StringPtr(password),
+	}
+
+	if opts.Username != "" {
+		request.UserName = tc.StringPtr(opts.Username)
+	}
+
+	_, err := p.lighthouse.ResetInstancesPassword(request)
+
+	return err
+
+}
+
 // Destroy an existing instance
 func (p *TencentLighthouseDriver) DestroyNode(node *compute.Node) error {
 
@@ -197,18 +319,36 @@ func (p *TencentLighthouseDriver) GetNodeState(node *compute.Node) (compute.Node
 		return "", nil
 	}
 
-	instance := resp.Response.InstanceSet[0]
+	return p.toNode(resp.Response.InstanceSet[0]).State, nil
 
-	// TODO: fix mapping
-	state := compute.NodeState(*instance.InstanceState)
+}
 
-	return state, nil
+// Get console session of instance, only vnc is supported
+func (p *TencentLighthouseDriver) GetNodeConsole(node *compute.Node, opts *compute.ConsoleOpts) (*compute.ConsoleSession, error) {
 
-}
+	if opts != nil && opts.Type != "" && opts.Type != compute.ConsoleTypeVNC {
+		return nil, compute.NotSupportedError
+	}
+
+	resp, err := p.lighthouse.DescribeInstanceVncUrl(&lighthouse.DescribeInstanceVncUrlRequest{
+		InstanceId: &node.Id,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// 链接有效期 15 秒，仅可使用一次
+	session := &compute.ConsoleSession{
+		Type:      compute.ConsoleTypeVNC,
+		Provider:  "tencent_lighthouse",
+		NodeId:    node.Id,
+		Url:       *resp.Response.InstanceVncUrl,
+		ExpiredAt: time.Now().Add(15 * time.Second),
+	}
+
+	return session, nil
 
-// Get the Console url for instance
-func (p *TencentLighthouseDriver) GetNodeConsole(node *compute.Node) (string, error) {
-	return "", nil
 }
 
 // Get the public IP address of instance
@@ -221,9 +361,69 @@ func (p *TencentLighthouseDriver) GetNodePrivateIp(node *compute.Node) (string,
 	return "", nil
 }
 
-// List all available storage volumes for instance
-func (p *TencentLighthouseDriver) ListVolumes(node *compute.Node) ([]*compute.StorageVolume, error) {
-	return nil, nil
+// List all available storage volumes for instance, tags are matched locally
+func (p *TencentLighthouseDriver) ListVolumes(node *compute.Node, filter *compute.VolumeFilter) ([]*compute.StorageVolume, error) {
+
+	limit := int64(100)
+
+	request := &lighthouse.DescribeDisksRequest{
+		Filters: []*lighthouse.Filter{p.filter("instance-id", node.Id)},
+		Limit:   &limit,
+	}
+
+	volumes := []*compute.StorageVolume{}
+	ids := []string{}
+
+	for offset := int64(0); ; offset += limit {
+
+		request.Offset = &offset
+		resp, err := p.lighthouse.DescribeDisks(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, disk := range resp.Response.DiskSet {
+
+			state, ok := lighthouseVolumeStates[*disk.DiskState]
+			if !ok {
+				state = compute.StorageVolumeStateUNKNOWN
+			}
+
+			volumes = append(volumes, &compute.StorageVolume{
+				Id:    *disk.DiskId,
+				Name:  *disk.DiskName,
+				Type:  *disk.DiskType,
+				Size:  int(*disk.DiskSize),
+				State: state,
+			})
+			ids = append(ids, *disk.DiskId)
+
+		}
+
+		if int64(len(resp.Response.DiskSet)) < limit || offset+limit >= *resp.Response.TotalCount {
+			break
+		}
+
+	}
+
+	tags, err := p.client.ResourceTags("lighthouse", "disk", ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*compute.StorageVolume{}
+
+	for _, volume := range volumes {
+		volume.Tags = tags[volume.Id]
+		if filter == nil || compute.MatchTags(volume.Tags, filter.Tags) {
+			result = append(result, volume)
+		}
+	}
+
+	return result, nil
+
 }
 
 // Attach volume to instance
@@ -236,9 +436,78 @@ func (p *TencentLighthouseDriver) DetachVolume(node *compute.Node, volume *compu
 	return nil
 }
 
-// List all snapshots for instance
-func (p *TencentLighthouseDriver) ListSnapshots(node *compute.Node) ([]*compute.VolumeSnapshot, error) {
-	return nil, nil
+// List all snapshots for instance, tags are matched locally
+func (p *TencentLighthouseDriver) ListSnapshots(node *compute.Node, filter *compute.SnapshotFilter) ([]*compute.VolumeSnapshot, error) {
+
+	limit := int64(100)
+
+	request := &lighthouse.DescribeSnapshotsRequest{
+		Filters: []*lighthouse.Filter{p.filter("instance-id", node.Id)},
+		Limit:   &limit,
+	}
+
+	snapshots := []*compute.VolumeSnapshot{}
+	ids := []string{}
+
+	for offset := int64(0); ; offset += limit {
+
+		request.Offset = &offset
+		resp, err := p.lighthouse.DescribeSnapshots(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range resp.Response.SnapshotSet {
+
+			snapshot := &compute.VolumeSnapshot{
+				Id:    *item.SnapshotId,
+				Name:  *item.SnapshotName,
+				Size:  int(*item.DiskSize),
+				State: compute.StorageVolumeStateUNKNOWN,
+			}
+
+			switch *item.SnapshotState {
+			case "NORMAL":
+				snapshot.State = compute.StorageVolumeStateAVAILABLE
+			case "CREATING":
+				snapshot.State = compute.StorageVolumeStateCREATING
+			case "ROLLBACKING":
+				snapshot.State = compute.StorageVolumeStateUPDATING
+			}
+
+			if item.CreatedTime != nil {
+				snapshot.CreatedAt, _ = time.Parse(time.RFC3339, *item.CreatedTime)
+			}
+
+			snapshots = append(snapshots, snapshot)
+			ids = append(ids, snapshot.Id)
+
+		}
+
+		if int64(len(resp.Response.SnapshotSet)) < limit || offset+limit >= *resp.Response.TotalCount {
+			break
+		}
+
+	}
+
+	tags, err := p.client.ResourceTags("lighthouse", "snapshot", ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*compute.VolumeSnapshot{}
+
+	for _, snapshot := range snapshots {
+		snapshot.Tags = tags[snapshot.Id]
+		if filter == nil || compute.MatchTags(snapshot.Tags, filter.Tags) {
+			result = append(result, snapshot)
+		}
+	}
+
+	return result, nil
+
 }
 
 // Create snapshot for instance
@@ -256,9 +525,81 @@ func (p *TencentLighthouseDriver) ApplySnapshot(node *compute.Node, snapshot *co
 	return nil
 }
 
-// List all available images for instance
-func (p *TencentLighthouseDriver) ListImages() ([]*compute.NodeImage, error) {
-	return nil, nil
+// List all available images for instance, only private images carry tags
+func (p *TencentLighthouseDriver) ListImages(filter *compute.ImageFilter) ([]*compute.NodeImage, error) {
+
+	limit := int64(100)
+
+	request := &lighthouse.DescribeBlueprintsRequest{
+		Limit: &limit,
+	}
+
+	images := []*compute.NodeImage{}
+	ids := []string{}
+
+	for offset := int64(0); ; offset += limit {
+
+		request.Offset = &offset
+		resp, err := p.lighthouse.DescribeBlueprints(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, blueprint := range resp.Response.BlueprintSet {
+
+			image := &compute.NodeImage{
+				Id:     *blueprint.BlueprintId,
+				Name:   *blueprint.BlueprintName,
+				OSType: compute.Linux,
+				State:  compute.NodeImageStatePENDING,
+				Extra: map[string]interface{}{
+					"BlueprintType": *blueprint.BlueprintType,
+				},
+			}
+
+			if blueprint.PlatformType != nil && *blueprint.PlatformType == "WINDOWS" {
+				image.OSType = compute.Windows
+			}
+
+			if blueprint.BlueprintState != nil && *blueprint.BlueprintState == "NORMAL" {
+				image.State = compute.NodeImageStateACCEPTED
+			}
+
+			images = append(images, image)
+
+			if *blueprint.BlueprintType == "PRIVATE" {
+				ids = append(ids, image.Id)
+			}
+
+		}
+
+		if int64(len(resp.Response.BlueprintSet)) < limit || offset+limit >= *resp.Response.TotalCount {
+			break
+		}
+
+	}
+
+	tags, err := p.client.ResourceTags("lighthouse", "blueprint", ids)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*compute.NodeImage{}
+
+	for _, image := range images {
+		image.Tags = tags[image.Id]
+		if image.Tags == nil {
+			image.Tags = map[string]string{}
+		}
+		if filter == nil || compute.MatchTags(image.Tags, filter.Tags) {
+			result = append(result, image)
+		}
+	}
+
+	return result, nil
+
 }
 
 // Apply Image to instance
@@ -268,7 +609,50 @@ func (p *TencentLighthouseDriver) ApplyImage(node *compute.Node, image *compute.
 
 // List all available sizes for instance
 func (p *TencentLighthouseDriver) ListSizes() ([]*compute.NodeSize, error) {
-	return nil, nil
+
+	resp, err := p.lighthouse.DescribeBundles(&lighthouse.DescribeBundlesRequest{})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sizes := []*compute.NodeSize{}
+
+	for _, bundle := range resp.Response.BundleSet {
+
+		// 内存单位 GB，系统盘单位 GB，公网带宽单位 Mbps，价格为月价
+		size := &compute.NodeSize{
+			Id:           *bundle.BundleId,
+			Name:         *bundle.BundleId,
+			Provider:     "tencent_lighthouse",
+			Region:       p.rq.RegionId,
+			Architecture: compute.X86_X64,
+			Cpu:          int(*bundle.CPU),
+			Ram:          int(*bundle.Memory) * 1024,
+			Disk:         int(*bundle.SystemDiskSize),
+			Bandwidth:    int(*bundle.InternetMaxBandwidthOut),
+			Extra: map[string]interface{}{
+				"MonthlyTraffic": *bundle.MonthlyTraffic,
+			},
+		}
+
+		if bundle.Price != nil && bundle.Price.InstancePrice != nil {
+			price := bundle.Price.InstancePrice
+			if price.OriginalBundlePrice != nil {
+				size.Price = *price.OriginalBundlePrice
+				size.Currency = "CNY"
+			}
+			if price.Currency != nil && *price.Currency != "" {
+				size.Currency = *price.Currency
+			}
+		}
+
+		sizes = append(sizes, size)
+
+	}
+
+	return sizes, nil
+
 }
 
 // Resize instance
@@ -280,3 +664,307 @@ func (p *TencentLighthouseDriver) ResizeNode(node *compute.Node, opts *compute.N
 func (p *TencentLighthouseDriver) ListLocations() ([]*compute.Location, error) {
 	return nil, nil
 }
+
+// Renew prepaid instance by months, one month by default
+func (p *TencentLighthouseDriver) RenewNode(node *compute.Node, period int) error {
+
+	if period <= 0 {
+		period = 1
+	}
+
+	_, err := p.lighthouse.RenewInstances(&lighthouse.RenewInstancesRequest{
+		InstanceIds: []*string{&node.Id},
+		InstanceChargePrepaid: &lighthouse.InstanceChargePrepaid{
+			Period: tc.Int64Ptr(int64(period)),
+		},
+	})
+
+	return err
+
+}
+
+// Enable or disable auto renewal of prepaid instance
+func (p *TencentLighthouseDriver) SetAutoRenew(node *compute.Node, enabled bool) error {
+
+	flag := "NOTIFY_AND_MANUAL_RENEW"
+	if enabled {
+		flag = "NOTIFY_AND_AUTO_RENEW"
+	}
+
+	_, err := p.lighthouse.ModifyInstancesRenewFlag(&lighthouse.ModifyInstancesRenewFlagRequest{
+		InstanceIds: []*string{&node.Id},
+		RenewFlag:   &flag,
+	})
+
+	return err
+
+}
+
+// Switch instance between prepaid and postpaid, period in months for prepaid
+func (p *TencentLighthouseDriver) ModifyChargeType(node *compute.Node, chargeType compute.ChargeType, period int) error {
+
+	return compute.NotSupportedError // 仅支持包年包月
+
+}
+
+// Modify public bandwidth in Mbps by switching to a bundle with the same cpu and memory
+func (p *TencentLighthouseDriver) ModifyNodeBandwidth(node *compute.Node, bandwidth int, chargeType compute.InternetChargeType) error {
+
+	if chargeType != "" && chargeType != node.InternetCharge {
+		return compute.NotSupportedError
+	}
+
+	if node.Size == nil {
+		return compute.NotSupportedError
+	}
+
+	// 带宽与套餐绑定，查找可变更的同配置套餐
+	bundleId := ""
+
+	limit := int64(100)
+	for offset := int64(0); bundleId == ""; offset += limit {
+		resp, err := p.lighthouse.DescribeModifyInstanceBundles(&lighthouse.DescribeModifyInstanceBundlesRequest{
+			InstanceId: tc.StringPtr(node.Id),
+			Offset:     tc.Int64Ptr(offset),
+			Limit:      tc.Int64Ptr(limit),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		for _, item := range resp.Response.ModifyBundleSet {
+			bundle := item.Bundle
+			if bundle == nil || item.ModifyBundleState == nil || *item.ModifyBundleState != "AVAILABLE" {
+				continue
+			}
+			if bundle.CPU == nil || bundle.Memory == nil || bundle.InternetMaxBandwidthOut == nil {
+				continue
+			}
+			if int(*bundle.CPU) == node.Size.Cpu && int(*bundle.Memory)*1024 == node.Size.Ram && int(*bundle.InternetMaxBandwidthOut) == bandwidth {
+				bundleId = *bundle.BundleId
+				break
+			}
+		}
+
+		if len(resp.Response.ModifyBundleSet) < int(limit) || offset+limit >= *resp.Response.TotalCount {
+			break
+		}
+	}
+
+	if bundleId == "" {
+		return compute.NotSupportedError
+	}
+
+	_, err := p.lighthouse.ModifyInstancesBundle(&lighthouse.ModifyInstancesBundleRequest{
+		InstanceIds: []*string{&node.Id},
+		BundleId:    tc.StringPtr(bundleId),
+		AutoVoucher: tc.BoolPtr(true),
+	})
+
+	if err == nil {
+		node.Bandwidth = bandwidth
+		node.Size.Id = bundleId
+	}
+
+	return err
+
+}
+
+// Run script on instances through cloud agent
+func (p *TencentLighthouseDriver) RunCommand(nodes []*compute.Node, script string, opts *compute.CommandOpts) (*compute.CommandInvocation, error) {
+
+	return tatRunCommand(p.client, nodes, script, opts)
+
+}
+
+// Get per instance results of command invocation
+func (p *TencentLighthouseDriver) GetCommandResults(invocation *compute.CommandInvocation) ([]*compute.CommandResult, error) {
+
+	return tatCommandResults(p.client, invocation)
+
+}
+
+// Add or overwrite tags of resources
+func (p *TencentLighthouseDriver) TagResources(resourceType compute.ResourceType, ids []string, tags map[string]string) error {
+
+	resources, err := p.client.ResourceNames("lighthouse", p.resourcePrefix(resourceType), ids)
+
+	if err != nil {
+		return err
+	}
+
+	return p.client.TagResources(resources, tags)
+
+}
+
+// Remove tags from resources by key
+func (p *TencentLighthouseDriver) UntagResources(resourceType compute.ResourceType, ids []string, keys []string) error {
+
+	resources, err := p.client.ResourceNames("lighthouse", p.resourcePrefix(resourceType), ids)
+
+	if err != nil {
+		return err
+	}
+
+	return p.client.UntagResources(resources, keys)
+
+}
+
+// 资源六段式前缀
+func (p *TencentLighthouseDriver) resourcePrefix(resourceType compute.ResourceType) string {
+
+	switch resourceType {
+	case compute.ResourceVolume:
+		return "disk"
+	case compute.ResourceSnapshot:
+		return "snapshot"
+	case compute.ResourceImage:
+		return "blueprint"
+	}
+
+	return "instance"
+
+}
+
+// 分页查询实例，镜像等条件不支持服务端过滤，在本地补充过滤
+func (p *TencentLighthouseDriver) describeNodes(request *lighthouse.DescribeInstancesRequest, filter *compute.NodeFilter) ([]*compute.Node, error) {
+
+	limit := int64(100)
+	request.Limit = &limit
+
+	var nodes []*compute.Node
+
+	for offset := int64(0); ; offset += limit {
+
+		request.Offset = &offset
+		resp, err := p.lighthouse.DescribeInstances(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instance := range resp.Response.InstanceSet {
+			if node := p.toNode(instance); filter.Match(node) {
+				nodes = append(nodes, node)
+			}
+		}
+
+		if int64(len(resp.Response.InstanceSet)) < limit || offset+limit >= *resp.Response.TotalCount {
+			break
+		}
+
+	}
+
+	return nodes, nil
+
+}
+
+// 过滤条件
+func (p *TencentLighthouseDriver) filter(name string, values ...string) *lighthouse.Filter {
+
+	items := []*string{}
+	for _, v := range values {
+		items = append(items, tc.StringPtr(v))
+	}
+
+	return &lighthouse.Filter{Name: &name, Values: items}
+
+}
+
+// 标签过滤条件，值为空时仅匹配标签键
+func (p *TencentLighthouseDriver) tagFilter(key, value string) *lighthouse.Filter {
+
+	if value == "" {
+		name := "tag-key"
+		return &lighthouse.Filter{Name: &name, Values: []*string{&key}}
+	}
+
+	name := "tag:" + key
+	return &lighthouse.Filter{Name: &name, Values: []*string{&value}}
+
+}
+
+// 转换实例信息
+func (p *TencentLighthouseDriver) toNode(instance *lighthouse.Instance) *compute.Node {
+
+	state, ok := lighthouseNodeStates[*instance.InstanceState]
+	if !ok {
+		state = compute.NodeStateUNKNOWN
+	}
+
+	// TODO: fix mapping
+	osType := compute.OSType(*instance.OsName)
+
+	node := &compute.Node{
+		Id:    *instance.InstanceId,
+		Name:  *instance.InstanceName,
+		State: state,
+		Size: &compute.NodeSize{
+			Id:  *instance.BundleId,
+			Cpu: int(*instance.CPU),
+			Ram: int(*instance.Memory) * 1024,
+		},
+		Image: &compute.NodeImage{
+			Id:     *instance.BlueprintId,
+			Name:   *instance.OsName,
+			OSType: osType,
+		},
+		Location: &compute.Location{
+			Id: *instance.Zone,
+		},
+		Tags: map[string]string{},
+	}
+
+	if len(instance.PublicAddresses) > 0 {
+		node.PublicIp = *instance.PublicAddresses[0]
+	}
+
+	if len(instance.PrivateAddresses) > 0 {
+		node.PrivateIp = *instance.PrivateAddresses[0]
+	}
+
+	for _, tag := range instance.Tags {
+		node.Tags[*tag.Key] = *tag.Value
+	}
+
+	if instance.CreatedTime != nil {
+		node.CreatedAt, _ = time.Parse(time.RFC3339, *instance.CreatedTime)
+	}
+
+	if instance.ExpiredTime != nil {
+		node.ExpiredAt, _ = time.Parse(time.RFC3339, *instance.ExpiredTime)
+	}
+
+	if instance.RenewFlag != nil {
+		node.AutoRenew = *instance.RenewFlag == "NOTIFY_AND_AUTO_RENEW"
+	}
+
+	node.InternetCharge = compute.InternetChargeUNKNOWN
+
+	if net := instance.InternetAccessible; net != nil {
+		if net.InternetMaxBandwidthOut != nil {
+			node.Bandwidth = int(*net.InternetMaxBandwidthOut)
+		}
+		if net.InternetChargeType != nil {
+			node.InternetCharge = tencentInternetChargeType(*net.InternetChargeType)
+		}
+	}
+
+	chargeType := ""
+	if instance.InstanceChargeType != nil {
+		chargeType = *instance.InstanceChargeType
+	}
+
+	switch chargeType {
+	case "PREPAID":
+		node.ChargeType = compute.ChargeTypePREPAID
+	case "POSTPAID_BY_HOUR":
+		node.ChargeType = compute.ChargeTypePOSTPAID
+	default:
+		node.ChargeType = compute.ChargeTypeUNKNOWN
+	}
+
+	return node
+
+}
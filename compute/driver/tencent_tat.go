@@ -0,0 +1,144 @@
+package drivers
+
+import (
+	"encoding/base64"
+
+	"github.com/rehiy/cloudgo/compute"
+	"github.com/rehiy/cloudgo/provider/tencent"
+)
+
+// 自动化助手 https://cloud.tencent.com/document/api/1340
+
+var tatCommandTypes = map[compute.CommandType]string{
+	"":                            "SHELL",
+	compute.CommandTypeSHELL:      "SHELL",
+	compute.CommandTypePOWERSHELL: "POWERSHELL",
+	compute.CommandTypeBAT:        "BAT",
+}
+
+// 执行命令
+
+func tatRunCommand(client *tencent.Client, nodes []*compute.Node, script string, opts *compute.CommandOpts) (*compute.CommandInvocation, error) {
+
+	if opts == nil {
+		opts = &compute.CommandOpts{}
+	}
+
+	ids := []string{}
+	for _, node := range nodes {
+		ids = append(ids, node.Id)
+	}
+
+	payload := map[string]any{
+		"Content":     base64.StdEncoding.EncodeToString([]byte(script)),
+		"InstanceIds": ids,
+		"CommandType": tatCommandTypes[opts.Type],
+		"SaveCommand": false,
+	}
+
+	if opts.Timeout > 0 {
+		payload["Timeout"] = opts.Timeout
+	}
+	if opts.WorkingDir != "" {
+		payload["WorkingDirectory"] = opts.WorkingDir
+	}
+	if opts.Username != "" {
+		payload["Username"] = opts.Username
+	}
+
+	result := struct {
+		CommandId    string
+		InvocationId string
+	}{}
+
+	err := client.Request("tat", "2020-10-28", "RunCommand", payload, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	invocation := &compute.CommandInvocation{
+		Id:      result.InvocationId,
+		NodeIds: ids,
+		Extra:   map[string]interface{}{"CommandId": result.CommandId},
+	}
+
+	return invocation, nil
+
+}
+
+// 查询执行结果
+
+func tatCommandResults(client *tencent.Client, invocation *compute.CommandInvocation) ([]*compute.CommandResult, error) {
+
+	payload := map[string]any{
+		"Filters": []map[string]any{
+			{"Name": "invocation-id", "Values": []string{invocation.Id}},
+		},
+		"HideOutput": false,
+		"Limit":      100,
+	}
+
+	results := []*compute.CommandResult{}
+
+	for offset := 0; ; offset += 100 {
+
+		result := struct {
+			TotalCount        int
+			InvocationTaskSet []struct {
+				InstanceId string
+				TaskStatus string
+				TaskResult struct {
+					ExitCode int
+					Output   string
+				}
+				ErrorInfo string
+			}
+		}{}
+
+		payload["Offset"] = offset
+		err := client.Request("tat", "2020-10-28", "DescribeInvocationTasks", payload, &result)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, task := range result.InvocationTaskSet {
+			output, _ := base64.StdEncoding.DecodeString(task.TaskResult.Output)
+			results = append(results, &compute.CommandResult{
+				NodeId:   task.InstanceId,
+				Status:   tatCommandStatus(task.TaskStatus),
+				ExitCode: task.TaskResult.ExitCode,
+				Stdout:   string(output),
+				Stderr:   task.ErrorInfo,
+			})
+		}
+
+		if len(result.InvocationTaskSet) < 100 || offset+100 >= result.TotalCount {
+			break
+		}
+
+	}
+
+	return results, nil
+
+}
+
+// 任务状态
+
+func tatCommandStatus(status string) compute.CommandStatus {
+
+	switch status {
+	case "PENDING", "DELIVERING", "DELIVER_DELAYED":
+		return compute.CommandStatusPENDING
+	case "RUNNING", "CANCELLING":
+		return compute.CommandStatusRUNNING
+	case "SUCCESS":
+		return compute.CommandStatusSUCCESS
+	case "TIMEOUT", "TASK_TIMEOUT":
+		return compute.CommandStatusTIMEOUT
+	case "CANCELLED", "TERMINATED":
+		return compute.CommandStatusCANCELLED
+	}
+
+	return compute.CommandStatusFAILED
+
+}
@@ -0,0 +1,108 @@
+package compute
+
+import (
+	"strings"
+	"time"
+)
+
+// Check node matches all conditions of filter
+func (f *NodeFilter) Match(node *Node) bool {
+
+	if f == nil {
+		return true
+	}
+
+	if len(f.Ids) > 0 && !contains(f.Ids, node.Id) {
+		return false
+	}
+
+	if f.Name != "" && node.Name != f.Name {
+		return false
+	}
+
+	if f.NamePrefix != "" && !strings.HasPrefix(node.Name, f.NamePrefix) {
+		return false
+	}
+
+	if len(f.States) > 0 {
+		found := false
+		for _, state := range f.States {
+			if node.State == state {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.Zone != "" && (node.Location == nil || node.Location.Id != f.Zone) {
+		return false
+	}
+
+	if f.VpcId != "" && node.VpcId != f.VpcId {
+		return false
+	}
+
+	if f.PrivateIp != "" && node.PrivateIp != f.PrivateIp {
+		return false
+	}
+
+	if f.PublicIp != "" && node.PublicIp != f.PublicIp {
+		return false
+	}
+
+	if f.ImageId != "" && (node.Image == nil || node.Image.Id != f.ImageId) {
+		return false
+	}
+
+	return MatchTags(node.Tags, f.Tags)
+
+}
+
+// Keep nodes matching filter
+func FilterNodes(nodes []*Node, filter *NodeFilter) []*Node {
+
+	result := []*Node{}
+
+	for _, node := range nodes {
+		if filter.Match(node) {
+			result = append(result, node)
+		}
+	}
+
+	return result
+
+}
+
+// Keep prepaid nodes without auto renewal expiring within duration
+func ExpiringNodes(nodes []*Node, within time.Duration) []*Node {
+
+	result := []*Node{}
+	deadline := time.Now().Add(within)
+
+	for _, node := range nodes {
+		if node.ChargeType != ChargeTypePREPAID || node.AutoRenew || node.ExpiredAt.IsZero() {
+			continue
+		}
+		if node.ExpiredAt.Before(deadline) {
+			result = append(result, node)
+		}
+	}
+
+	return result
+
+}
+
+func contains(list []string, item string) bool {
+
+	for _, v := range list {
+		if v == item {
+			return true
+		}
+	}
+
+	return false
+
+}
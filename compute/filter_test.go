@@ -0,0 +1,81 @@
+package compute
+
+import (
+	"testing"
+)
+
+func TestNodeFilterMatch(t *testing.T) {
+
+	node := &Node{
+		Id:        "i-1",
+		Name:      "web-1",
+		State:     NodeStateRUNNING,
+		VpcId:     "vpc-1",
+		PrivateIp: "10.0.0.2",
+		PublicIp:  "203.0.113.2",
+		Location:  &Location{Id: "zone-a"},
+		Image:     &NodeImage{Id: "img-1"},
+		Tags:      map[string]string{"env": "prod", "role": "web"},
+	}
+
+	tests := []struct {
+		name   string
+		filter *NodeFilter
+		want   bool
+	}{
+		{"nil filter", nil, true},
+		{"empty filter", &NodeFilter{}, true},
+		{"id in list", &NodeFilter{Ids: []string{"i-0", "i-1"}}, true},
+		{"id not in list", &NodeFilter{Ids: []string{"i-2"}}, false},
+		{"exact name", &NodeFilter{Name: "web-1"}, true},
+		{"other name", &NodeFilter{Name: "web"}, false},
+		{"name prefix", &NodeFilter{NamePrefix: "web-"}, true},
+		{"other name prefix", &NodeFilter{NamePrefix: "db-"}, false},
+		{"state in list", &NodeFilter{States: []NodeState{NodeStateSTOPPED, NodeStateRUNNING}}, true},
+		{"state not in list", &NodeFilter{States: []NodeState{NodeStateSTOPPED}}, false},
+		{"zone", &NodeFilter{Zone: "zone-a"}, true},
+		{"other zone", &NodeFilter{Zone: "zone-b"}, false},
+		{"vpc", &NodeFilter{VpcId: "vpc-2"}, false},
+		{"private ip", &NodeFilter{PrivateIp: "10.0.0.2"}, true},
+		{"public ip", &NodeFilter{PublicIp: "203.0.113.3"}, false},
+		{"image", &NodeFilter{ImageId: "img-1"}, true},
+		{"other image", &NodeFilter{ImageId: "img-2"}, false},
+		{"tag value", &NodeFilter{Tags: map[string]string{"env": "prod"}}, true},
+		{"tag key only", &NodeFilter{Tags: map[string]string{"role": ""}}, true},
+		{"tag other value", &NodeFilter{Tags: map[string]string{"env": "dev"}}, false},
+		{"missing tag", &NodeFilter{Tags: map[string]string{"team": ""}}, false},
+		{"all conditions", &NodeFilter{NamePrefix: "web", Zone: "zone-a", Tags: map[string]string{"env": "prod"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(node); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestNodeFilterMatchMissingFields(t *testing.T) {
+
+	node := &Node{Id: "i-1"}
+
+	tests := []struct {
+		name   string
+		filter *NodeFilter
+	}{
+		{"zone without location", &NodeFilter{Zone: "zone-a"}},
+		{"image without image", &NodeFilter{ImageId: "img-1"}},
+		{"tag without tags", &NodeFilter{Tags: map[string]string{"env": ""}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.filter.Match(node) {
+				t.Error("Match() = true, want false")
+			}
+		})
+	}
+
+}
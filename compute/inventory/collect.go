@@ -0,0 +1,139 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rehiy/cloudgo/compute"
+	drivers "github.com/rehiy/cloudgo/compute/driver"
+	"github.com/rehiy/cloudgo/provider"
+)
+
+// Available driver factories, keyed by Account.Provider
+var Drivers = map[string]DriverFactory{
+	"alibaba_ecs": func(rq *provider.ReqeustParam) compute.ComputeProvider {
+		return drivers.NewAlibabaEcsDriver(rq)
+	},
+	"alibaba_swas": func(rq *provider.ReqeustParam) compute.ComputeProvider {
+		return drivers.NewAlibabaSwasDriver(rq)
+	},
+	"tencent_cvm": func(rq *provider.ReqeustParam) compute.ComputeProvider {
+		rq.Service = "cvm"
+		return drivers.NewTencentCvmDriver(rq)
+	},
+	"tencent_lighthouse": func(rq *provider.ReqeustParam) compute.ComputeProvider {
+		rq.Service = "lighthouse"
+		return drivers.NewTencentLighthouseDriver(rq)
+	},
+}
+
+// Collect nodes, volumes and zones of all accounts and regions
+func Collect(accounts []*Account, opts *Options) *Inventory {
+
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 8
+	}
+
+	inv := &Inventory{}
+
+	mu := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	sem := make(chan struct{}, concurrency)
+
+	for _, account := range accounts {
+		for _, region := range account.Regions {
+			scope := Scope{account.Name, account.Provider, region}
+
+			factory, ok := Drivers[account.Provider]
+			if !ok {
+				inv.Errors = append(inv.Errors, &TargetError{
+					scope, "NewDriver", errors.New("unsupported provider"),
+				})
+				continue
+			}
+
+			rq := &provider.ReqeustParam{
+				SecretId:  account.SecretId,
+				SecretKey: account.SecretKey,
+				RegionId:  region,
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				part := collectTarget(scope, factory(rq), opts)
+
+				mu.Lock()
+				inv.Nodes = append(inv.Nodes, part.Nodes...)
+				inv.Volumes = append(inv.Volumes, part.Volumes...)
+				inv.Zones = append(inv.Zones, part.Zones...)
+				inv.Errors = append(inv.Errors, part.Errors...)
+				mu.Unlock()
+			}()
+		}
+	}
+
+	wg.Wait()
+
+	return inv
+
+}
+
+// collect one account and region
+func collectTarget(scope Scope, driver compute.ComputeProvider, opts *Options) (inv *Inventory) {
+
+	inv = &Inventory{}
+
+	// 驱动异常不影响其他目标
+	defer func() {
+		if r := recover(); r != nil {
+			inv.Errors = append(inv.Errors, &TargetError{scope, "Panic", fmt.Errorf("%v", r)})
+		}
+	}()
+
+	locations, err := driver.ListLocations()
+	if err != nil {
+		inv.Errors = append(inv.Errors, &TargetError{scope, "ListLocations", err})
+	}
+
+	for _, location := range locations {
+		inv.Zones = append(inv.Zones, &Zone{scope, location})
+	}
+
+	nodes, err := driver.ListNodes(nil)
+	if err != nil {
+		inv.Errors = append(inv.Errors, &TargetError{scope, "ListNodes", err})
+		return inv
+	}
+
+	for _, node := range nodes {
+		inv.Nodes = append(inv.Nodes, &Node{scope, node})
+
+		if opts.SkipVolumes {
+			continue
+		}
+
+		volumes, err := driver.ListVolumes(node, nil)
+		if err != nil {
+			inv.Errors = append(inv.Errors, &TargetError{scope, "ListVolumes", err})
+			continue
+		}
+
+		for _, volume := range volumes {
+			inv.Volumes = append(inv.Volumes, &Volume{scope, node.Id, volume})
+		}
+	}
+
+	return inv
+
+}
@@ -0,0 +1,86 @@
+package inventory
+
+import (
+	"github.com/rehiy/cloudgo/compute"
+	"github.com/rehiy/cloudgo/provider"
+)
+
+// create a compute driver for one account and region
+
+type DriverFactory func(rq *provider.ReqeustParam) compute.ComputeProvider
+
+// cloud account with the regions to scan
+
+type Account struct {
+	Name      string
+	Provider  string
+	SecretId  string
+	SecretKey string
+	Regions   []string
+}
+
+// options for collecting inventory
+
+type Options struct {
+	Concurrency int  // max targets scanned at once, default 8
+	SkipVolumes bool // skip ListVolumes for every node
+}
+
+// account, provider and region of a resource
+
+type Scope struct {
+	Account  string
+	Provider string
+	Region   string
+}
+
+// merged inventory result
+
+type Inventory struct {
+	Nodes   []*Node
+	Volumes []*Volume
+	Zones   []*Zone
+	Errors  []*TargetError
+}
+
+// compute instance with scope
+
+type Node struct {
+	Scope
+	*compute.Node
+}
+
+// storage volume with scope
+
+type Volume struct {
+	Scope
+	NodeId string
+	*compute.StorageVolume
+}
+
+// compute location with scope
+
+type Zone struct {
+	Scope
+	*compute.Location
+}
+
+// error of one target
+
+type TargetError struct {
+	Scope
+	Action string
+	Err    error
+}
+
+func (e *TargetError) Error() string {
+
+	return e.Account + "/" + e.Provider + "/" + e.Region + ": " + e.Action + ": " + e.Err.Error()
+
+}
+
+func (e *TargetError) Unwrap() error {
+
+	return e.Err
+
+}
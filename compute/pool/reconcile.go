@@ -0,0 +1,179 @@
+package pool
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rehiy/cloudgo/compute"
+)
+
+// Reconcile pool to desired count, only plan when dryRun
+func Reconcile(provider compute.ComputeProvider, spec *Spec, dryRun bool) (*Result, error) {
+
+	plan, err := NewPlan(provider, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return &Result{Plan: plan}, nil
+	}
+
+	return Apply(provider, plan), nil
+
+}
+
+// Compare pool nodes with spec and plan create and destroy actions
+func NewPlan(provider compute.ComputeProvider, spec *Spec) (*Plan, error) {
+
+	if spec.Template == nil || spec.NamePrefix == "" || spec.Desired < 0 {
+		return nil, errors.New("invalid pool spec")
+	}
+
+	nodes, err := provider.ListNodes(&compute.NodeFilter{
+		NamePrefix: spec.NamePrefix,
+		Tags:       spec.Tags,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{Spec: spec, Actions: []*Action{}}
+
+	// 异常节点同样计入单次销毁上限
+	drain := 0
+
+	healthy := []*compute.Node{}
+	for _, node := range nodes {
+		switch node.State {
+		case compute.NodeStateTERMINATED:
+			continue
+		case compute.NodeStateERROR:
+			if spec.MaxDrain == 0 || drain < spec.MaxDrain {
+				plan.Actions = append(plan.Actions, &Action{ActionDestroy, node.Name, node, "error state"})
+				drain++
+			}
+		default:
+			healthy = append(healthy, node)
+		}
+		plan.Nodes = append(plan.Nodes, node)
+	}
+
+	plan.Healthy = len(healthy)
+
+	// 扩容，包含替换异常节点
+	if missing := spec.Desired - len(healthy); missing > 0 {
+		if spec.MaxSurge > 0 && missing > spec.MaxSurge {
+			missing = spec.MaxSurge
+		}
+		for _, name := range nextNames(spec.NamePrefix, plan.Nodes, missing) {
+			plan.Actions = append(plan.Actions, &Action{ActionCreate, name, nil, "below desired count"})
+		}
+	}
+
+	// 缩容，优先移除未运行和最新创建的节点
+	if extra := len(healthy) - spec.Desired; extra > 0 {
+		if spec.MaxDrain > 0 && extra > spec.MaxDrain-drain {
+			extra = spec.MaxDrain - drain
+		}
+		sort.SliceStable(healthy, func(i, j int) bool {
+			ri := healthy[i].State == compute.NodeStateRUNNING
+			rj := healthy[j].State == compute.NodeStateRUNNING
+			if ri != rj {
+				return rj
+			}
+			return healthy[i].CreatedAt.After(healthy[j].CreatedAt)
+		})
+		for _, node := range healthy[:extra] {
+			plan.Actions = append(plan.Actions, &Action{ActionDestroy, node.Name, node, "above desired count"})
+		}
+	}
+
+	return plan, nil
+
+}
+
+// Execute actions of plan, failed actions are collected and do not stop others
+func Apply(provider compute.ComputeProvider, plan *Plan) *Result {
+
+	result := &Result{Plan: plan}
+
+	for _, action := range plan.Actions {
+		switch action.Type {
+		case ActionCreate:
+			opts := *plan.Spec.Template
+			opts.Name = action.Name
+
+			// 创建时即打上池标签，避免节点脱离池管理
+			opts.Tags = map[string]string{}
+			for k, v := range plan.Spec.Template.Tags {
+				opts.Tags[k] = v
+			}
+			for k, v := range plan.Spec.Tags {
+				opts.Tags[k] = v
+			}
+
+			node, err := provider.CreateNode(&opts)
+			if err == nil && node == nil {
+				err = errors.New("no node returned")
+			}
+			if err != nil {
+				result.Errors = append(result.Errors, &ActionError{action, err})
+				continue
+			}
+
+			action.Node = node
+
+			// 不支持创建时打标签的平台单独补打，失败时该动作视为失败
+			if !compute.MatchTags(node.Tags, plan.Spec.Tags) {
+				err = provider.TagResources(compute.ResourceNode, []string{node.Id}, plan.Spec.Tags)
+				if err != nil {
+					result.Errors = append(result.Errors, &ActionError{action, err})
+					continue
+				}
+				if node.Tags == nil {
+					node.Tags = map[string]string{}
+				}
+				for k, v := range plan.Spec.Tags {
+					node.Tags[k] = v
+				}
+			}
+
+			result.Created = append(result.Created, node)
+
+		case ActionDestroy:
+			if err := provider.DestroyNode(action.Node); err != nil {
+				result.Errors = append(result.Errors, &ActionError{action, err})
+				continue
+			}
+
+			result.Destroyed = append(result.Destroyed, action.Node)
+		}
+	}
+
+	return result
+
+}
+
+// 生成未占用的节点名称，格式为 prefix-N
+func nextNames(prefix string, nodes []*compute.Node, count int) []string {
+
+	used := map[string]bool{}
+	for _, node := range nodes {
+		used[node.Name] = true
+	}
+
+	names := []string{}
+	for i := 1; len(names) < count; i++ {
+		name := strings.TrimSuffix(prefix, "-") + "-" + strconv.Itoa(i)
+		if !used[name] {
+			names = append(names, name)
+		}
+	}
+
+	return names
+
+}
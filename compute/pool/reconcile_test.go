@@ -0,0 +1,338 @@
+package pool
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rehiy/cloudgo/compute"
+)
+
+// 模拟平台，仅实现调和所需的方法
+type fakeProvider struct {
+	compute.ComputeProvider
+	nodes     []*compute.Node
+	filter    *compute.NodeFilter
+	created   []*compute.NodeCreateOpts
+	destroyed []string
+	tagged    map[string]map[string]string
+	tagErr    error
+	createTag bool // 创建时即打标签
+	nilNode   bool // 创建成功但未返回节点
+}
+
+func (p *fakeProvider) ListNodes(filter *compute.NodeFilter) ([]*compute.Node, error) {
+
+	p.filter = filter
+	return compute.FilterNodes(p.nodes, filter), nil
+
+}
+
+func (p *fakeProvider) CreateNode(opts *compute.NodeCreateOpts) (*compute.Node, error) {
+
+	p.created = append(p.created, opts)
+
+	if p.nilNode {
+		return nil, nil
+	}
+
+	node := &compute.Node{Id: "new-" + opts.Name, Name: opts.Name, Tags: map[string]string{}}
+	if p.createTag {
+		for k, v := range opts.Tags {
+			node.Tags[k] = v
+		}
+	}
+
+	return node, nil
+
+}
+
+func (p *fakeProvider) DestroyNode(node *compute.Node) error {
+
+	p.destroyed = append(p.destroyed, node.Name)
+	return nil
+
+}
+
+func (p *fakeProvider) TagResources(resourceType compute.ResourceType, ids []string, tags map[string]string) error {
+
+	if p.tagErr != nil {
+		return p.tagErr
+	}
+
+	if p.tagged == nil {
+		p.tagged = map[string]map[string]string{}
+	}
+	for _, id := range ids {
+		p.tagged[id] = tags
+	}
+
+	return nil
+
+}
+
+func poolNode(name string, state compute.NodeState, age time.Duration) *compute.Node {
+
+	return &compute.Node{
+		Id:        "id-" + name,
+		Name:      name,
+		State:     state,
+		CreatedAt: time.Now().Add(-age),
+		Tags:      map[string]string{"pool": "web"},
+	}
+
+}
+
+type planAction struct {
+	Type ActionType
+	Name string
+}
+
+func planActions(plan *Plan) []planAction {
+
+	actions := []planAction{}
+	for _, action := range plan.Actions {
+		actions = append(actions, planAction{action.Type, action.Name})
+	}
+
+	return actions
+
+}
+
+func TestNewPlan(t *testing.T) {
+
+	running := compute.NodeStateRUNNING
+
+	tests := []struct {
+		name    string
+		nodes   []*compute.Node
+		spec    Spec
+		want    []planAction
+		healthy int
+	}{
+		{
+			name:    "scale up from empty",
+			spec:    Spec{Desired: 2},
+			want:    []planAction{{ActionCreate, "web-1"}, {ActionCreate, "web-2"}},
+			healthy: 0,
+		},
+		{
+			name:    "at desired count",
+			nodes:   []*compute.Node{poolNode("web-1", running, time.Hour), poolNode("web-2", running, time.Hour)},
+			spec:    Spec{Desired: 2},
+			want:    []planAction{},
+			healthy: 2,
+		},
+		{
+			name:    "new names skip used ones",
+			nodes:   []*compute.Node{poolNode("web-2", running, time.Hour)},
+			spec:    Spec{Desired: 3},
+			want:    []planAction{{ActionCreate, "web-1"}, {ActionCreate, "web-3"}},
+			healthy: 1,
+		},
+		{
+			name:    "max surge limits creates",
+			spec:    Spec{Desired: 5, MaxSurge: 2},
+			want:    []planAction{{ActionCreate, "web-1"}, {ActionCreate, "web-2"}},
+			healthy: 0,
+		},
+		{
+			name:    "terminated nodes are ignored",
+			nodes:   []*compute.Node{poolNode("web-1", compute.NodeStateTERMINATED, time.Hour)},
+			spec:    Spec{Desired: 1},
+			want:    []planAction{{ActionCreate, "web-1"}},
+			healthy: 0,
+		},
+		{
+			name:    "error node is replaced",
+			nodes:   []*compute.Node{poolNode("web-1", compute.NodeStateERROR, time.Hour)},
+			spec:    Spec{Desired: 1},
+			want:    []planAction{{ActionDestroy, "web-1"}, {ActionCreate, "web-2"}},
+			healthy: 0,
+		},
+		{
+			name: "scale down removes stopped then newest",
+			nodes: []*compute.Node{
+				poolNode("web-1", running, 3*time.Hour),
+				poolNode("web-2", running, time.Hour),
+				poolNode("web-3", compute.NodeStateSTOPPED, 2*time.Hour),
+				poolNode("web-4", running, 2*time.Hour),
+			},
+			spec:    Spec{Desired: 2},
+			want:    []planAction{{ActionDestroy, "web-3"}, {ActionDestroy, "web-2"}},
+			healthy: 4,
+		},
+		{
+			name: "max drain limits destroys",
+			nodes: []*compute.Node{
+				poolNode("web-1", running, 3*time.Hour),
+				poolNode("web-2", running, 2*time.Hour),
+				poolNode("web-3", running, time.Hour),
+			},
+			spec:    Spec{Desired: 0, MaxDrain: 1},
+			want:    []planAction{{ActionDestroy, "web-3"}},
+			healthy: 3,
+		},
+		{
+			name: "max drain counts error nodes",
+			nodes: []*compute.Node{
+				poolNode("web-1", compute.NodeStateERROR, time.Hour),
+				poolNode("web-2", compute.NodeStateERROR, time.Hour),
+				poolNode("web-3", running, time.Hour),
+				poolNode("web-4", running, time.Hour),
+			},
+			spec:    Spec{Desired: 1, MaxDrain: 1},
+			want:    []planAction{{ActionDestroy, "web-1"}},
+			healthy: 2,
+		},
+		{
+			name:    "nodes outside pool are not listed",
+			nodes:   []*compute.Node{poolNode("db-1", running, time.Hour), {Name: "web-9", State: running}},
+			spec:    Spec{Desired: 1},
+			want:    []planAction{{ActionCreate, "web-1"}},
+			healthy: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := tt.spec
+			spec.Template = &compute.NodeCreateOpts{}
+			spec.NamePrefix = "web-"
+			spec.Tags = map[string]string{"pool": "web"}
+
+			provider := &fakeProvider{nodes: tt.nodes}
+
+			plan, err := NewPlan(provider, &spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := planActions(plan); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("actions = %v, want %v", got, tt.want)
+			}
+
+			if plan.Healthy != tt.healthy {
+				t.Errorf("healthy = %d, want %d", plan.Healthy, tt.healthy)
+			}
+
+			if provider.filter.NamePrefix != spec.NamePrefix || !reflect.DeepEqual(provider.filter.Tags, spec.Tags) {
+				t.Errorf("filter = %+v", provider.filter)
+			}
+		})
+	}
+
+}
+
+func TestNewPlanInvalidSpec(t *testing.T) {
+
+	tests := []struct {
+		name string
+		spec *Spec
+	}{
+		{"missing template", &Spec{NamePrefix: "web-", Desired: 1}},
+		{"missing prefix", &Spec{Template: &compute.NodeCreateOpts{}, Desired: 1}},
+		{"negative desired", &Spec{Template: &compute.NodeCreateOpts{}, NamePrefix: "web-", Desired: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPlan(&fakeProvider{}, tt.spec); err == nil {
+				t.Error("want error for invalid spec")
+			}
+		})
+	}
+
+}
+
+func TestApply(t *testing.T) {
+
+	spec := &Spec{
+		Template:   &compute.NodeCreateOpts{Tags: map[string]string{"team": "ops"}},
+		NamePrefix: "web-",
+		Tags:       map[string]string{"pool": "web"},
+	}
+
+	tests := []struct {
+		name      string
+		createTag bool
+		tagErr    error
+		created   int
+		errors    int
+		tagged    bool
+	}{
+		{"tags set at creation", true, nil, 1, 0, false},
+		{"tags added after creation", false, nil, 1, 0, true},
+		{"tag failure fails action", false, errors.New("denied"), 0, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &fakeProvider{createTag: tt.createTag, tagErr: tt.tagErr}
+
+			plan := &Plan{
+				Spec: spec,
+				Actions: []*Action{
+					{ActionCreate, "web-1", nil, "below desired count"},
+					{ActionDestroy, "web-0", poolNode("web-0", compute.NodeStateERROR, time.Hour), "error state"},
+				},
+			}
+
+			result := Apply(provider, plan)
+
+			if len(result.Created) != tt.created || len(result.Errors) != tt.errors {
+				t.Fatalf("created %d, errors %v", len(result.Created), result.Errors)
+			}
+
+			if len(result.Destroyed) != 1 || provider.destroyed[0] != "web-0" {
+				t.Errorf("destroyed = %v", provider.destroyed)
+			}
+
+			want := map[string]string{"team": "ops", "pool": "web"}
+			if !reflect.DeepEqual(provider.created[0].Tags, want) {
+				t.Errorf("create tags = %v, want %v", provider.created[0].Tags, want)
+			}
+
+			if (provider.tagged != nil) != tt.tagged {
+				t.Errorf("tagged = %v, want %v", provider.tagged, tt.tagged)
+			}
+
+			node := plan.Actions[0].Node
+			if node == nil {
+				t.Fatal("action node not set")
+			}
+			if tt.tagErr != nil && node.Tags["pool"] != "" {
+				t.Error("failed tagging set node tags")
+			}
+			if tt.tagErr == nil && node.Tags["pool"] != "web" {
+				t.Errorf("node tags = %v", node.Tags)
+			}
+		})
+	}
+
+	if len(spec.Template.Tags) != 1 {
+		t.Errorf("template tags modified: %v", spec.Template.Tags)
+	}
+
+}
+
+func TestApplyNilNode(t *testing.T) {
+
+	plan := &Plan{
+		Spec:    &Spec{Template: &compute.NodeCreateOpts{}, NamePrefix: "web-", Tags: map[string]string{"pool": "web"}},
+		Actions: []*Action{{ActionCreate, "web-1", nil, "below desired count"}},
+	}
+
+	provider := &fakeProvider{nilNode: true}
+	result := Apply(provider, plan)
+
+	if len(result.Created) != 0 || len(result.Errors) != 1 {
+		t.Errorf("created %v, errors %v", result.Created, result.Errors)
+	}
+
+	if provider.tagged != nil {
+		t.Errorf("tagged = %v, want none", provider.tagged)
+	}
+
+}
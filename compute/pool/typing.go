@@ -0,0 +1,67 @@
+package pool
+
+import (
+	"github.com/rehiy/cloudgo/compute"
+)
+
+// pool of identical nodes, selected by name prefix and tags
+
+type Spec struct {
+	Template   *compute.NodeCreateOpts
+	Desired    int
+	NamePrefix string
+	Tags       map[string]string
+	MaxSurge   int // max nodes created in one reconcile, 0 means unlimited
+	MaxDrain   int // max nodes destroyed in one reconcile, error nodes included, 0 means unlimited
+}
+
+// kind of planned change
+
+type ActionType string
+
+const (
+	ActionCreate  ActionType = "create"
+	ActionDestroy ActionType = "destroy"
+)
+
+// planned change, Node is nil for create until applied
+
+type Action struct {
+	Type   ActionType
+	Name   string
+	Node   *compute.Node
+	Reason string
+}
+
+// reconcile plan, returned as is in dry-run
+
+type Plan struct {
+	Spec    *Spec
+	Nodes   []*compute.Node
+	Healthy int
+	Actions []*Action
+}
+
+// result of applying plan
+
+type Result struct {
+	Plan      *Plan
+	Created   []*compute.Node
+	Destroyed []*compute.Node
+	Errors    []*ActionError
+}
+
+// failed action with cause
+
+type ActionError struct {
+	Action *Action
+	Err    error
+}
+
+func (e *ActionError) Error() string {
+	return string(e.Action.Type) + " " + e.Action.Name + ": " + e.Err.Error()
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.Err
+}
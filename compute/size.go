@@ -0,0 +1,143 @@
+package compute
+
+import (
+	"sort"
+	"strings"
+)
+
+// hours of a billing month, used to convert hourly prices to monthly
+
+const HoursPerMonth = 720
+
+// criteria for matching sizes, MaxPrice is the monthly price in Currency (default CNY)
+
+type SizeCriteria struct {
+	MinCpu       int
+	MinRam       int // MiB
+	MinDisk      int // GiB
+	MinBandwidth int // Mbps
+	MinGpu       int
+	MaxPrice     float64
+	Currency     string
+	Architecture Architecture
+}
+
+// errors of providers failed to list sizes
+
+type SizeErrors []error
+
+func (e SizeErrors) Error() string {
+
+	msgs := []string{}
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+
+}
+
+// Find sizes matching criteria across providers, cheapest and smallest first
+// Failed providers are skipped and returned as SizeErrors with the other results
+func FindSizes(criteria *SizeCriteria, providers ...ComputeProvider) ([]*NodeSize, error) {
+
+	var sizes []*NodeSize
+	var errs SizeErrors
+
+	for _, provider := range providers {
+		list, err := provider.ListSizes()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		sizes = append(sizes, list...)
+	}
+
+	if len(errs) > 0 {
+		return FilterSizes(sizes, criteria), errs
+	}
+
+	return FilterSizes(sizes, criteria), nil
+
+}
+
+// Filter and rank sizes, sizes with unknown price are ranked last
+// and never match when MaxPrice is set
+func FilterSizes(sizes []*NodeSize, criteria *SizeCriteria) []*NodeSize {
+
+	if criteria == nil {
+		criteria = &SizeCriteria{}
+	}
+
+	currency := criteria.Currency
+	if currency == "" {
+		currency = "CNY"
+	}
+
+	result := []*NodeSize{}
+
+	for _, size := range sizes {
+		if size.Cpu < criteria.MinCpu || size.Ram < criteria.MinRam {
+			continue
+		}
+		if size.Disk < criteria.MinDisk || size.Bandwidth < criteria.MinBandwidth {
+			continue
+		}
+		if size.Gpu < criteria.MinGpu {
+			continue
+		}
+		if criteria.MaxPrice > 0 {
+			if size.Price <= 0 || size.Price > criteria.MaxPrice {
+				continue
+			}
+			if !strings.EqualFold(size.Currency, currency) {
+				continue
+			}
+		}
+		if criteria.Architecture != "" && size.Architecture != criteria.Architecture {
+			continue
+		}
+		result = append(result, size)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if (a.Price > 0) != (b.Price > 0) {
+			return a.Price > 0
+		}
+		if a.Currency != b.Currency {
+			return a.Currency < b.Currency
+		}
+		if a.Price != b.Price {
+			return a.Price < b.Price
+		}
+		if a.Cpu != b.Cpu {
+			return a.Cpu < b.Cpu
+		}
+		if a.Ram != b.Ram {
+			return a.Ram < b.Ram
+		}
+		return a.Gpu < b.Gpu
+	})
+
+	return result
+
+}
+
+// Parse vendor architecture name
+func ParseArchitecture(name string) Architecture {
+
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "x86", "x86_64", "x64", "amd64", "intel", "amd":
+		return X86_X64
+	case "i386", "i686", "x86_32":
+		return I386
+	case "arm64", "aarch64", "arm":
+		return Arm64
+	case "arm32", "armv7":
+		return Arm
+	}
+
+	return UnknownArch
+
+}
@@ -0,0 +1,134 @@
+package compute
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func sizeIds(sizes []*NodeSize) []string {
+
+	ids := []string{}
+	for _, size := range sizes {
+		ids = append(ids, size.Id)
+	}
+
+	return ids
+
+}
+
+func TestFilterSizes(t *testing.T) {
+
+	sizes := []*NodeSize{
+		{Id: "small", Cpu: 1, Ram: 1024, Disk: 40, Bandwidth: 1, Price: 30, Currency: "CNY", Architecture: X86_X64},
+		{Id: "medium", Cpu: 2, Ram: 4096, Disk: 60, Bandwidth: 5, Price: 90, Currency: "CNY", Architecture: X86_X64},
+		{Id: "large", Cpu: 4, Ram: 8192, Disk: 80, Bandwidth: 10, Price: 200, Currency: "CNY", Architecture: X86_X64},
+		{Id: "arm", Cpu: 2, Ram: 4096, Disk: 60, Bandwidth: 5, Price: 60, Currency: "CNY", Architecture: Arm64},
+		{Id: "gpu", Cpu: 8, Ram: 32768, Disk: 100, Gpu: 1, Price: 3000, Currency: "CNY", Architecture: X86_X64},
+		{Id: "usd", Cpu: 2, Ram: 4096, Disk: 60, Bandwidth: 5, Price: 10, Currency: "USD", Architecture: X86_X64},
+		{Id: "unpriced", Cpu: 1, Ram: 1024, Disk: 40, Architecture: X86_X64},
+	}
+
+	tests := []struct {
+		name     string
+		criteria *SizeCriteria
+		want     []string
+	}{
+		{
+			name:     "nil criteria ranks priced first by currency and price",
+			criteria: nil,
+			want:     []string{"small", "arm", "medium", "large", "gpu", "usd", "unpriced"},
+		},
+		{
+			name:     "minimum cpu and ram",
+			criteria: &SizeCriteria{MinCpu: 2, MinRam: 4096},
+			want:     []string{"arm", "medium", "large", "gpu", "usd"},
+		},
+		{
+			name:     "minimum disk and bandwidth",
+			criteria: &SizeCriteria{MinDisk: 60, MinBandwidth: 10},
+			want:     []string{"large"},
+		},
+		{
+			name:     "minimum gpu",
+			criteria: &SizeCriteria{MinGpu: 1},
+			want:     []string{"gpu"},
+		},
+		{
+			name:     "architecture",
+			criteria: &SizeCriteria{Architecture: Arm64},
+			want:     []string{"arm"},
+		},
+		{
+			name:     "max price in default currency drops unpriced and other currencies",
+			criteria: &SizeCriteria{MaxPrice: 100},
+			want:     []string{"small", "arm", "medium"},
+		},
+		{
+			name:     "max price in other currency",
+			criteria: &SizeCriteria{MaxPrice: 100, Currency: "usd"},
+			want:     []string{"usd"},
+		},
+		{
+			name:     "nothing matches",
+			criteria: &SizeCriteria{MinCpu: 64},
+			want:     []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sizeIds(FilterSizes(sizes, tt.criteria))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FilterSizes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestFilterSizesTieBreak(t *testing.T) {
+
+	sizes := []*NodeSize{
+		{Id: "b", Cpu: 2, Ram: 2048, Price: 50, Currency: "CNY"},
+		{Id: "a", Cpu: 2, Ram: 1024, Price: 50, Currency: "CNY"},
+		{Id: "c", Cpu: 1, Ram: 4096, Price: 50, Currency: "CNY"},
+	}
+
+	got := sizeIds(FilterSizes(sizes, nil))
+	want := []string{"c", "a", "b"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FilterSizes() = %v, want %v", got, want)
+	}
+
+}
+
+func TestSizeErrors(t *testing.T) {
+
+	err := SizeErrors{errors.New("tencent: denied"), errors.New("alibaba: timeout")}
+
+	if err.Error() != "tencent: denied; alibaba: timeout" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+}
+
+func TestParseArchitecture(t *testing.T) {
+
+	tests := map[string]Architecture{
+		"x86_64":  X86_X64,
+		" AMD64 ": X86_X64,
+		"i686":    I386,
+		"aarch64": Arm64,
+		"armv7":   Arm,
+		"mips":    UnknownArch,
+	}
+
+	for name, want := range tests {
+		if got := ParseArchitecture(name); got != want {
+			t.Errorf("ParseArchitecture(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+}
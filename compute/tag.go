@@ -0,0 +1,15 @@
+package compute
+
+// Check tags contain every key and value of selector, empty value matches any value
+func MatchTags(tags, selector map[string]string) bool {
+
+	for k, v := range selector {
+		val, ok := tags[k]
+		if !ok || (v != "" && val != v) {
+			return false
+		}
+	}
+
+	return true
+
+}
@@ -8,7 +8,7 @@ import (
 
 type ComputeProvider interface {
 	// List all instance
-	ListNodes() ([]*Node, error)
+	ListNodes(filter *NodeFilter) ([]*Node, error)
 
 	// Detail instance by Id
 	DetailNode(id string) (*Node, error)
@@ -16,6 +16,12 @@ type ComputeProvider interface {
 	// Create new instance
 	CreateNode(opts *NodeCreateOpts) (*Node, error)
 
+	// Update name, description or hostname of instance
+	UpdateNode(node *Node, attrs *NodeUpdateAttrs) error
+
+	// Reset login password of instance
+	ResetNodePassword(node *Node, password string, opts *PasswordResetOpts) error
+
 	// Destroy an existing instance
 	DestroyNode(node *Node) error
 
@@ -31,8 +37,8 @@ type ComputeProvider interface {
 	// Get the current state of instance
 	GetNodeState(node *Node) (NodeState, error)
 
-	// Get the Console url for instance
-	GetNodeConsole(node *Node) (string, error)
+	// Get console session of instance, default vnc
+	GetNodeConsole(node *Node, opts *ConsoleOpts) (*ConsoleSession, error)
 
 	// Get the public IP address of instance
 	GetNodePublicIp(node *Node) (string, error)
@@ -41,7 +47,7 @@ type ComputeProvider interface {
 	GetNodePrivateIp(node *Node) (string, error)
 
 	// List all available storage volumes for instance
-	ListVolumes(node *Node) ([]*StorageVolume, error)
+	ListVolumes(node *Node, filter *VolumeFilter) ([]*StorageVolume, error)
 
 	// Attach volume to instance
 	AttachVolume(node *Node, snapshot *StorageVolume) error
@@ -50,7 +56,7 @@ type ComputeProvider interface {
 	DetachVolume(node *Node, snapshot *StorageVolume) error
 
 	// List all snapshots for instance
-	ListSnapshots(node *Node) ([]*VolumeSnapshot, error)
+	ListSnapshots(node *Node, filter *SnapshotFilter) ([]*VolumeSnapshot, error)
 
 	// Create snapshot for instance
 	CreateSnapshot(node *Node, name string) (*VolumeSnapshot, error)
@@ -62,7 +68,7 @@ type ComputeProvider interface {
 	ApplySnapshot(node *Node, snapshot *VolumeSnapshot) error
 
 	// List all available images for instance
-	ListImages() ([]*NodeImage, error)
+	ListImages(filter *ImageFilter) ([]*NodeImage, error)
 
 	// Apply Image to instance
 	ApplyImage(node *Node, image *NodeImage) error
@@ -75,33 +81,197 @@ type ComputeProvider interface {
 
 	// List all available locations for instance
 	ListLocations() ([]*Location, error)
+
+	// Renew prepaid instance by months, one month by default
+	RenewNode(node *Node, period int) error
+
+	// Enable or disable auto renewal of prepaid instance
+	SetAutoRenew(node *Node, enabled bool) error
+
+	// Switch instance between prepaid and postpaid, period in months for prepaid, one month by default
+	ModifyChargeType(node *Node, chargeType ChargeType, period int) error
+
+	// Modify public bandwidth in Mbps, empty charge type keeps the current one
+	ModifyNodeBandwidth(node *Node, bandwidth int, chargeType InternetChargeType) error
+
+	// Run script on instances through cloud agent
+	RunCommand(nodes []*Node, script string, opts *CommandOpts) (*CommandInvocation, error)
+
+	// Get per instance results of command invocation
+	GetCommandResults(invocation *CommandInvocation) ([]*CommandResult, error)
+
+	// Add or overwrite tags of resources
+	TagResources(resourceType ResourceType, ids []string, tags map[string]string) error
+
+	// Remove tags from resources by key
+	UntagResources(resourceType ResourceType, ids []string, keys []string) error
 }
 
-// compute instance
+// Define Spot Provider interface for preemptible instance
+
+type SpotProvider interface {
+	// Query current spot prices of size, empty location means all zones
+	ListSpotPrices(size *NodeSize, location *Location) ([]*SpotPrice, error)
+
+	// List pending interruption notices of spot instances
+	ListSpotInterruptions(nodes []*Node) ([]*SpotInterruption, error)
+}
+
+// Define Address Provider interface for elastic public IP
+
+type AddressProvider interface {
+	// List all addresses with binding status
+	ListAddresses() ([]*Address, error)
+
+	// Allocate new address
+	AllocateAddress(opts *AddressCreateOpts) (*Address, error)
+
+	// Bind address to instance
+	AssociateAddress(address *Address, node *Node) error
+
+	// Bind address to elastic network interface
+	AssociateAddressEni(address *Address, eniId string) error
+
+	// Unbind address from instance or network interface
+	DisassociateAddress(address *Address) error
+
+	// Modify bandwidth of address in Mbps
+	ModifyAddressBandwidth(address *Address, bandwidth int) error
+
+	// Release address
+	ReleaseAddress(address *Address) error
+}
+
+// compute instance, Bandwidth is the public outbound bandwidth in Mbps
 
 type Node struct {
-	Id        string
-	Name      string
-	State     NodeState
-	Size      *NodeSize
-	Image     *NodeImage
-	PublicIp  string
-	PrivateIp string
-	Location  *Location
-	CreatedAt time.Time
-	Extra     map[string]interface{}
+	Id             string
+	Name           string
+	State          NodeState
+	Size           *NodeSize
+	Image          *NodeImage
+	PublicIp       string
+	PrivateIp      string
+	VpcId          string
+	Location       *Location
+	CreatedAt      time.Time
+	ExpiredAt      time.Time
+	AutoRenew      bool
+	ChargeType     ChargeType
+	Bandwidth      int
+	InternetCharge InternetChargeType
+	Spot           bool
+	SpotPriceLimit float64
+	Tags           map[string]string
+	Extra          map[string]interface{}
+}
+
+// filter for listing compute, empty fields match all
+
+type NodeFilter struct {
+	Ids        []string
+	Name       string
+	NamePrefix string
+	States     []NodeState
+	Zone       string
+	VpcId      string
+	PrivateIp  string
+	PublicIp   string
+	ImageId    string
+	Tags       map[string]string
 }
 
 // options for creating new compute
+// empty ChargeType uses the provider default, Period is in months for prepaid and defaults to 1
+// Tags are set in the create request, providers without support leave them to TagResources
 
 type NodeCreateOpts struct {
-	Name     string
-	Size     *NodeSize
-	Image    *NodeImage
-	Location *Location
+	Name           string
+	Size           *NodeSize
+	Image          *NodeImage
+	Location       *Location
+	SpotStrategy   SpotStrategy
+	SpotPriceLimit float64
+	ChargeType     ChargeType
+	Period         int
+	UserData       *UserData
+	Tags           map[string]string
+	Extra          map[string]interface{}
+}
+
+// attributes for updating compute, empty fields are kept
+// Reboot restarts a running instance when the change needs it to take effect
+
+type NodeUpdateAttrs struct {
+	Name        string
+	Description string
+	Hostname    string
+	Reboot      bool
+	Extra       map[string]interface{}
+}
+
+// options for resetting password, empty Username means the image default
+// ForceStop shuts down a running instance if the provider requires it
+
+type PasswordResetOpts struct {
+	Username  string
+	ForceStop bool
+	Reboot    bool
+	Extra     map[string]interface{}
+}
+
+// options for console session
+// Password sets vnc password (ECS) or is the login password (web)
+
+type ConsoleOpts struct {
+	Type     ConsoleType
+	Username string
+	Password string
 	Extra    map[string]interface{}
 }
 
+// console session, use EmbedUrl to get the page url for browser
+// Provider is the driver name, e.g. tencent_cvm, alibaba_ecs
+
+type ConsoleSession struct {
+	Type      ConsoleType
+	Provider  string
+	NodeId    string
+	Url       string
+	Password  string
+	Token     string
+	ExpiredAt time.Time
+	Extra     map[string]interface{}
+}
+
+// user data run on first boot, rendered as cloud-init or powershell for windows
+
+type UserData struct {
+	Hostname   string
+	Users      []*UserDataUser
+	SshKeys    []string // authorized keys of default user
+	Packages   []string
+	WriteFiles []*UserDataFile
+	RunCmd     []string
+	Script     string // raw script used as is, e.g. #!/bin/sh
+	Windows    bool
+}
+
+type UserDataUser struct {
+	Name    string
+	Groups  []string
+	Sudo    bool
+	Shell   string
+	SshKeys []string
+}
+
+type UserDataFile struct {
+	Path        string
+	Content     string
+	Permissions string // e.g. 0644
+	Owner       string // e.g. root:root
+}
+
 // options for resizing compute
 
 type NodeResizeOpts struct {
@@ -109,7 +279,35 @@ type NodeResizeOpts struct {
 	Extra map[string]interface{}
 }
 
-// compute snapshot
+// options for running command, Timeout in seconds
+
+type CommandOpts struct {
+	Type       CommandType
+	Timeout    int
+	WorkingDir string
+	Username   string
+	Extra      map[string]interface{}
+}
+
+// handle of command invocation
+
+type CommandInvocation struct {
+	Id      string
+	NodeIds []string
+	Extra   map[string]interface{}
+}
+
+// command result of one instance
+
+type CommandResult struct {
+	NodeId   string
+	Status   CommandStatus
+	ExitCode int
+	Stdout   string
+	Stderr   string
+}
+
+// compute volume
 
 type StorageVolume struct {
 	Id    string
@@ -117,6 +315,13 @@ type StorageVolume struct {
 	Type  string
 	Size  int
 	State StorageVolumeState
+	Tags  map[string]string
+}
+
+// filter for listing volume
+
+type VolumeFilter struct {
+	Tags map[string]string
 }
 
 // compute snapshot
@@ -127,6 +332,13 @@ type VolumeSnapshot struct {
 	Size      int
 	State     StorageVolumeState
 	CreatedAt time.Time
+	Tags      map[string]string
+}
+
+// filter for listing snapshot
+
+type SnapshotFilter struct {
+	Tags map[string]string
 }
 
 // compute image
@@ -136,14 +348,27 @@ type NodeImage struct {
 	Name   string
 	OSType OSType
 	State  NodeImageState
+	Tags   map[string]string
 	Extra  map[string]interface{}
 }
 
+// filter for listing image
+
+type ImageFilter struct {
+	Tags map[string]string
+}
+
 // compute size
+// Ram is in MiB, Disk is the bundled local/system disk in GiB,
+// Bandwidth is the bundled public bandwidth in Mbps (0 if chosen at purchase),
+// Price is the monthly list price in Currency (0 if unknown),
+// Provider is the driver name and Region the region the size was listed in
 
 type NodeSize struct {
 	Id           string
 	Name         string
+	Provider     string
+	Region       string
 	Architecture Architecture
 	Gpu          int
 	Cpu          int
@@ -151,9 +376,30 @@ type NodeSize struct {
 	Disk         int
 	Bandwidth    int
 	Price        float64
+	Currency     string
 	Extra        map[string]interface{}
 }
 
+// spot price of size in zone, Price and OriginPrice per hour
+
+type SpotPrice struct {
+	SizeId      string
+	Zone        string
+	Price       float64
+	OriginPrice float64
+	Timestamp   time.Time
+	Extra       map[string]interface{}
+}
+
+// interruption notice of spot instance, Time is when it will be reclaimed
+
+type SpotInterruption struct {
+	NodeId string
+	Action string
+	Time   time.Time
+	Extra  map[string]interface{}
+}
+
 // compute location
 
 type Location struct {
@@ -162,3 +408,27 @@ type Location struct {
 	Country string
 	Extra   map[string]interface{}
 }
+
+// elastic public address, Bandwidth in Mbps
+
+type Address struct {
+	Id         string
+	Ip         string
+	State      AddressState
+	Bandwidth  int
+	ChargeType InternetChargeType
+	Line       string
+	NodeId     string
+	EniId      string
+	CreatedAt  time.Time
+	Extra      map[string]interface{}
+}
+
+// options for allocating address, Line is the vendor ISP name (BGP if empty)
+
+type AddressCreateOpts struct {
+	Bandwidth  int
+	ChargeType InternetChargeType
+	Line       string
+	Extra      map[string]interface{}
+}
@@ -0,0 +1,170 @@
+package compute
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Render cloud-init yaml, or powershell script for windows
+// Script is returned as is when set, other fields are ignored
+func (u *UserData) Render() (string, error) {
+
+	if u.Script != "" {
+		return u.Script, nil
+	}
+
+	if u.Windows {
+		return u.renderPowerShell()
+	}
+
+	return u.renderCloudConfig()
+
+}
+
+// Render and encode as base64, limit is the max encoded size in bytes, 0 means unlimited
+func (u *UserData) Encode(limit int) (string, error) {
+
+	data, err := u.Render()
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(data))
+
+	if limit > 0 && len(encoded) > limit {
+		return "", UserDataTooLargeError
+	}
+
+	return encoded, nil
+
+}
+
+// 生成 cloud-init 配置
+func (u *UserData) renderCloudConfig() (string, error) {
+
+	config := map[string]interface{}{}
+
+	if u.Hostname != "" {
+		config["hostname"] = u.Hostname
+	}
+
+	if len(u.Users) > 0 {
+		users := []interface{}{"default"}
+		for _, user := range u.Users {
+			item := map[string]interface{}{"name": user.Name}
+			if len(user.Groups) > 0 {
+				item["groups"] = strings.Join(user.Groups, ", ")
+			}
+			if user.Sudo {
+				item["sudo"] = "ALL=(ALL) NOPASSWD:ALL"
+			}
+			if user.Shell != "" {
+				item["shell"] = user.Shell
+			}
+			if len(user.SshKeys) > 0 {
+				item["ssh_authorized_keys"] = user.SshKeys
+			}
+			users = append(users, item)
+		}
+		config["users"] = users
+	}
+
+	if len(u.SshKeys) > 0 {
+		config["ssh_authorized_keys"] = u.SshKeys
+	}
+
+	if len(u.Packages) > 0 {
+		config["packages"] = u.Packages
+	}
+
+	if len(u.WriteFiles) > 0 {
+		files := []interface{}{}
+		for _, file := range u.WriteFiles {
+			item := map[string]interface{}{
+				"path":    file.Path,
+				"content": file.Content,
+			}
+			if file.Permissions != "" {
+				item["permissions"] = file.Permissions
+			}
+			if file.Owner != "" {
+				item["owner"] = file.Owner
+			}
+			files = append(files, item)
+		}
+		config["write_files"] = files
+	}
+
+	if len(u.RunCmd) > 0 {
+		config["runcmd"] = u.RunCmd
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	return "#cloud-config\n" + string(data), nil
+
+}
+
+// 生成 Windows PowerShell 脚本
+func (u *UserData) renderPowerShell() (string, error) {
+
+	if len(u.Packages) > 0 {
+		return "", NotSupportedError
+	}
+
+	lines := []string{"#ps1_sysnative"}
+
+	if u.Hostname != "" {
+		lines = append(lines, "Rename-Computer -NewName "+psQuote(u.Hostname)+" -Force")
+	}
+
+	for _, user := range u.Users {
+		lines = append(lines, "net user "+psQuote(user.Name)+" /add")
+		for _, group := range user.Groups {
+			lines = append(lines, "net localgroup "+psQuote(group)+" "+psQuote(user.Name)+" /add")
+		}
+		if user.Sudo {
+			lines = append(lines, "net localgroup Administrators "+psQuote(user.Name)+" /add")
+		}
+	}
+
+	// OpenSSH 管理员公钥文件
+	keys := append([]string{}, u.SshKeys...)
+	for _, user := range u.Users {
+		keys = append(keys, user.SshKeys...)
+	}
+	if len(keys) > 0 {
+		path := `C:\ProgramData\ssh\administrators_authorized_keys`
+		lines = append(lines, "New-Item -ItemType Directory -Force -Path 'C:\\ProgramData\\ssh' | Out-Null")
+		lines = append(lines, "Set-Content -Path '"+path+"' -Value "+psHereString(strings.Join(keys, "\n")))
+		lines = append(lines, "icacls.exe '"+path+"' /inheritance:r /grant 'Administrators:F' /grant 'SYSTEM:F'")
+	}
+
+	for _, file := range u.WriteFiles {
+		lines = append(lines, "Set-Content -Path "+psQuote(file.Path)+" -Value "+psHereString(file.Content))
+	}
+
+	lines = append(lines, u.RunCmd...)
+
+	return strings.Join(lines, "\r\n") + "\r\n", nil
+
+}
+
+// PowerShell 单引号字符串
+func psQuote(s string) string {
+
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+
+}
+
+// PowerShell 原样多行字符串
+func psHereString(s string) string {
+
+	return "@'\r\n" + strings.ReplaceAll(s, "\n", "\r\n") + "\r\n'@"
+
+}
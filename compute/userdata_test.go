@@ -0,0 +1,180 @@
+package compute
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestUserDataRenderCloudConfig(t *testing.T) {
+
+	tests := []struct {
+		name string
+		data *UserData
+		want map[string]interface{}
+	}{
+		{
+			name: "empty",
+			data: &UserData{},
+			want: map[string]interface{}{},
+		},
+		{
+			name: "hostname and default user keys",
+			data: &UserData{Hostname: "web-1", SshKeys: []string{"ssh-ed25519 AAAA a@b"}},
+			want: map[string]interface{}{
+				"hostname":            "web-1",
+				"ssh_authorized_keys": []interface{}{"ssh-ed25519 AAAA a@b"},
+			},
+		},
+		{
+			name: "users keep default user",
+			data: &UserData{Users: []*UserDataUser{
+				{Name: "deploy", Groups: []string{"docker", "adm"}, Sudo: true, Shell: "/bin/bash", SshKeys: []string{"ssh-rsa BBBB"}},
+			}},
+			want: map[string]interface{}{
+				"users": []interface{}{
+					"default",
+					map[string]interface{}{
+						"name":                "deploy",
+						"groups":              "docker, adm",
+						"sudo":                "ALL=(ALL) NOPASSWD:ALL",
+						"shell":               "/bin/bash",
+						"ssh_authorized_keys": []interface{}{"ssh-rsa BBBB"},
+					},
+				},
+			},
+		},
+		{
+			name: "packages, files and commands",
+			data: &UserData{
+				Packages:   []string{"nginx"},
+				WriteFiles: []*UserDataFile{{Path: "/etc/app.conf", Content: "a: 1\n", Permissions: "0600", Owner: "root:root"}},
+				RunCmd:     []string{"systemctl enable --now nginx"},
+			},
+			want: map[string]interface{}{
+				"packages": []interface{}{"nginx"},
+				"write_files": []interface{}{
+					map[string]interface{}{"path": "/etc/app.conf", "content": "a: 1\n", "permissions": "0600", "owner": "root:root"},
+				},
+				"runcmd": []interface{}{"systemctl enable --now nginx"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tt.data.Render()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.HasPrefix(out, "#cloud-config\n") {
+				t.Fatalf("missing header: %q", out)
+			}
+
+			got := map[string]interface{}{}
+			if err := yaml.Unmarshal([]byte(out), &got); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Render() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestUserDataRenderScript(t *testing.T) {
+
+	data := &UserData{Script: "#!/bin/sh\necho hi\n", Hostname: "ignored", Windows: true}
+
+	out, err := data.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out != data.Script {
+		t.Errorf("Render() = %q, want script as is", out)
+	}
+
+}
+
+func TestUserDataRenderPowerShell(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		data    *UserData
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "hostname and quoting",
+			data: &UserData{Windows: true, Hostname: "o'neil"},
+			want: []string{"#ps1_sysnative\r\n", "Rename-Computer -NewName 'o''neil' -Force\r\n"},
+		},
+		{
+			name: "sudo user joins administrators",
+			data: &UserData{Windows: true, Users: []*UserDataUser{{Name: "ops", Groups: []string{"Users"}, Sudo: true}}},
+			want: []string{"net user 'ops' /add", "net localgroup 'Users' 'ops' /add", "net localgroup Administrators 'ops' /add"},
+		},
+		{
+			name: "keys go to administrators file",
+			data: &UserData{Windows: true, SshKeys: []string{"ssh-ed25519 AAAA"}, Users: []*UserDataUser{{Name: "ops", SshKeys: []string{"ssh-rsa BBBB"}}}},
+			want: []string{`administrators_authorized_keys' -Value @'` + "\r\nssh-ed25519 AAAA\r\nssh-rsa BBBB\r\n'@"},
+		},
+		{
+			name: "files and commands",
+			data: &UserData{Windows: true, WriteFiles: []*UserDataFile{{Path: `C:\app.conf`, Content: "a\nb"}}, RunCmd: []string{"Start-Service app"}},
+			want: []string{`Set-Content -Path 'C:\app.conf' -Value @'` + "\r\na\r\nb\r\n'@\r\n", "Start-Service app\r\n"},
+		},
+		{
+			name:    "packages not supported",
+			data:    &UserData{Windows: true, Packages: []string{"git"}},
+			wantErr: NotSupportedError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := tt.data.Render()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("Render() = %q, missing %q", out, want)
+				}
+			}
+		})
+	}
+
+}
+
+func TestUserDataEncode(t *testing.T) {
+
+	data := &UserData{Script: "#!/bin/sh\necho hi\n"}
+
+	encoded, err := data.Encode(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || string(decoded) != data.Script {
+		t.Errorf("Encode() = %q, decoded %q", encoded, decoded)
+	}
+
+	if _, err := data.Encode(len(encoded)); err != nil {
+		t.Errorf("Encode() at exact limit: %v", err)
+	}
+
+	if _, err := data.Encode(len(encoded) - 1); !errors.Is(err, UserDataTooLargeError) {
+		t.Errorf("Encode() over limit = %v, want UserDataTooLargeError", err)
+	}
+
+}
@@ -0,0 +1,78 @@
+package acme
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/rehiy/cloudgo/dns"
+
+	mdns "github.com/miekg/dns"
+)
+
+// 等待所有权威服务器返回挑战记录
+
+func waitPropagation(zone *dns.Zone, fqdn, value string, timeout, interval time.Duration) error {
+
+	servers := zone.DnsServers
+	if len(servers) == 0 {
+		nss, err := net.LookupNS(zone.Domain)
+		if err != nil {
+			return err
+		}
+		for _, ns := range nss {
+			servers = append(servers, ns.Host)
+		}
+	}
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		pending := ""
+		for _, server := range servers {
+			ok, err := queryTxt(server, fqdn, value)
+			if err != nil || !ok {
+				pending = server
+				break
+			}
+		}
+
+		if pending == "" {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("challenge record %s not found on %s after %s", fqdn, pending, timeout)
+		}
+
+		time.Sleep(interval)
+	}
+
+}
+
+// 向权威服务器查询 TXT 记录
+
+func queryTxt(server, fqdn, value string) (bool, error) {
+
+	msg := &mdns.Msg{}
+	msg.SetQuestion(mdns.Fqdn(fqdn), mdns.TypeTXT)
+	msg.RecursionDesired = false
+
+	client := &mdns.Client{Timeout: 5 * time.Second}
+	addr := net.JoinHostPort(strings.TrimSuffix(server, "."), "53")
+
+	resp, _, err := client.Exchange(msg, addr)
+	if err != nil {
+		return false, err
+	}
+
+	for _, rr := range resp.Answer {
+		if txt, ok := rr.(*mdns.TXT); ok && strings.Join(txt.Txt, "") == value {
+			return true, nil
+		}
+	}
+
+	return false, nil
+
+}
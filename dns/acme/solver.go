@@ -0,0 +1,183 @@
+package acme
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rehiy/cloudgo/dns"
+)
+
+const (
+	defaultTTL      = 600
+	defaultTimeout  = 2 * time.Minute
+	defaultInterval = 5 * time.Second
+)
+
+// DNS-01 challenge solver, implements lego challenge.Provider and challenge.ProviderTimeout
+
+type Solver struct {
+	provider dns.DnsProvider
+	options  *Options
+	mu       sync.Mutex
+	records  map[string]*presented
+}
+
+// challenge record kept for clean up
+
+type presented struct {
+	zone   *dns.Zone
+	record *dns.Record
+}
+
+func NewSolver(provider dns.DnsProvider, opts *Options) *Solver {
+
+	options := &Options{TTL: defaultTTL, Timeout: defaultTimeout, Interval: defaultInterval}
+
+	if opts != nil {
+		if opts.TTL > 0 {
+			options.TTL = opts.TTL
+		}
+		if opts.Timeout > 0 {
+			options.Timeout = opts.Timeout
+		}
+		if opts.Interval > 0 {
+			options.Interval = opts.Interval
+		}
+	}
+
+	return &Solver{provider: provider, options: options, records: map[string]*presented{}}
+
+}
+
+// Get fqdn and value of the challenge record for domain
+func ChallengeRecord(domain, keyAuth string) (string, string) {
+
+	sum := sha256.Sum256([]byte(keyAuth))
+	value := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	domain = strings.TrimPrefix(strings.TrimSuffix(domain, "."), "*.")
+
+	return "_acme-challenge." + domain + ".", value
+
+}
+
+// Create challenge record and wait until authoritative nameservers serve it
+func (s *Solver) Present(domain, token, keyAuth string) error {
+
+	fqdn, value := ChallengeRecord(domain, keyAuth)
+
+	zone, err := dns.FindZone(s.provider, fqdn)
+	if err != nil {
+		return err
+	}
+
+	if detail, err := s.provider.DetailZone(zone); err == nil && len(detail.DnsServers) > 0 {
+		zone.DnsServers = detail.DnsServers
+	}
+
+	if err := s.present(zone, fqdn, value); err != nil {
+		return err
+	}
+
+	return waitPropagation(zone, fqdn, value, s.options.Timeout, s.options.Interval)
+
+}
+
+// Delete challenge record, records of other SANs with the same name are kept
+func (s *Solver) CleanUp(domain, token, keyAuth string) error {
+
+	fqdn, value := ChallengeRecord(domain, keyAuth)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := fqdn + " " + value
+	item, ok := s.records[key]
+
+	if !ok {
+		zone, err := dns.FindZone(s.provider, fqdn)
+		if err != nil {
+			return err
+		}
+		record, err := s.find(zone, fqdn, value)
+		if err != nil || record == nil {
+			return err
+		}
+		item = &presented{zone, record}
+	}
+
+	if err := s.provider.DeleteRecord(item.zone, item.record); err != nil {
+		return err
+	}
+
+	delete(s.records, key)
+	return nil
+
+}
+
+// Propagation timeout and polling interval, used by lego
+func (s *Solver) Timeout() (time.Duration, time.Duration) {
+
+	return s.options.Timeout, s.options.Interval
+
+}
+
+// 创建挑战记录，已存在相同值时复用
+
+func (s *Solver) present(zone *dns.Zone, fqdn, value string) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := fqdn + " " + value
+	if _, ok := s.records[key]; ok {
+		return nil
+	}
+
+	record, err := s.find(zone, fqdn, value)
+	if err != nil {
+		return err
+	}
+
+	if record == nil {
+		record, err = s.provider.CreateRecord(zone, &dns.Record{
+			Name:  zone.RelativeName(fqdn),
+			Type:  dns.RecordTypeTXT,
+			Value: value,
+			TTL:   s.options.TTL,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	s.records[key] = &presented{zone, record}
+	return nil
+
+}
+
+// 查找相同名称和值的挑战记录
+
+func (s *Solver) find(zone *dns.Zone, fqdn, value string) (*dns.Record, error) {
+
+	records, err := s.provider.ListRecords(zone, &dns.RecordFilter{
+		Name: zone.RelativeName(fqdn),
+		Type: dns.RecordTypeTXT,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if strings.Trim(record.Value, `"`) == value {
+			return record, nil
+		}
+	}
+
+	return nil, nil
+
+}
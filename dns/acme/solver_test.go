@@ -0,0 +1,35 @@
+package acme
+
+import (
+	"testing"
+)
+
+func TestChallengeRecord(t *testing.T) {
+
+	// sha256 of "token.thumbprint" in unpadded base64url
+	value := "61rBZ_4knHblO0MNoxFsXZ_eTFUHum0B6IVRbhvUn5I"
+
+	tests := []struct {
+		domain string
+		fqdn   string
+	}{
+		{"example.com", "_acme-challenge.example.com."},
+		{"example.com.", "_acme-challenge.example.com."},
+		{"www.example.com", "_acme-challenge.www.example.com."},
+		{"*.example.com", "_acme-challenge.example.com."},
+		{"*.sub.example.com.", "_acme-challenge.sub.example.com."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			fqdn, got := ChallengeRecord(tt.domain, "token.thumbprint")
+			if fqdn != tt.fqdn {
+				t.Errorf("fqdn = %q, want %q", fqdn, tt.fqdn)
+			}
+			if got != value {
+				t.Errorf("value = %q, want %q", got, value)
+			}
+		})
+	}
+
+}
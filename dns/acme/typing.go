@@ -0,0 +1,13 @@
+package acme
+
+import (
+	"time"
+)
+
+// solver settings, zero values use defaults
+
+type Options struct {
+	TTL      int           // ttl of challenge records
+	Timeout  time.Duration // max wait for propagation
+	Interval time.Duration // delay between propagation checks
+}
@@ -14,37 +14,117 @@ const (
 type RecordType string
 
 const (
-	RecordTypeA          RecordType = "A"
-	RecordTypeAAAA       RecordType = "AAAA"
-	RecordTypeAFSDB      RecordType = "A"
-	RecordTypeALIAS      RecordType = "ALIAS"
-	RecordTypeCERT       RecordType = "CERT"
-	RecordTypeCNAME      RecordType = "CNAME"
-	RecordTypeDNAME      RecordType = "DNAME"
-	RecordTypeDNSKEY     RecordType = "DNSKEY"
-	RecordTypeDS         RecordType = "DS"
-	RecordTypeGEO        RecordType = "GEO"
-	RecordTypeHINFO      RecordType = "HINFO"
-	RecordTypeKEY        RecordType = "KEY"
-	RecordTypeLOC        RecordType = "LOC"
-	RecordTypeMX         RecordType = "MX"
-	RecordTypeNAPTR      RecordType = "NAPTR"
-	RecordTypeNS         RecordType = "NS"
-	RecordTypeNSEC       RecordType = "NSEC"
-	RecordTypeOPENPGPKEY RecordType = "OPENPGPKEY"
-	RecordTypePTR        RecordType = "PTR"
-	RecordTypeREDIRECT   RecordType = "REDIRECT"
-	RecordRP             RecordType = "RP"
-	RecordTypeRRSIG      RecordType = "RRSIG"
-	RecordTypeSOA        RecordType = "SOA"
-	RecordTypeSPF        RecordType = "SPF"
-	RecordTypeSRV        RecordType = "SRV"
-	RecordTypeSSHFP      RecordType = "SSHFP"
-	RecordTypeTLSA       RecordType = "TLSA"
-	RecordTypeTXT        RecordType = "TXT"
-	RecordTypeURL        RecordType = "URL"
-	RecordTypeWKS        RecordType = "WKS"
-	RecordTypeCAA        RecordType = "CAA"
+	RecordTypeA            RecordType = "A"
+	RecordTypeAAAA         RecordType = "AAAA"
+	RecordTypeAFSDB        RecordType = "AFSDB"
+	RecordTypeALIAS        RecordType = "ALIAS"
+	RecordTypeCERT         RecordType = "CERT"
+	RecordTypeCNAME        RecordType = "CNAME"
+	RecordTypeDNAME        RecordType = "DNAME"
+	RecordTypeDNSKEY       RecordType = "DNSKEY"
+	RecordTypeDS           RecordType = "DS"
+	RecordTypeFORWARD_URL  RecordType = "FORWARD_URL"
+	RecordTypeGEO          RecordType = "GEO"
+	RecordTypeHINFO        RecordType = "HINFO"
+	RecordTypeHTTPS        RecordType = "HTTPS"
+	RecordTypeKEY          RecordType = "KEY"
+	RecordTypeLOC          RecordType = "LOC"
+	RecordTypeMX           RecordType = "MX"
+	RecordTypeNAPTR        RecordType = "NAPTR"
+	RecordTypeNS           RecordType = "NS"
+	RecordTypeNSEC         RecordType = "NSEC"
+	RecordTypeOPENPGPKEY   RecordType = "OPENPGPKEY"
+	RecordTypePTR          RecordType = "PTR"
+	RecordTypeREDIRECT     RecordType = "REDIRECT"
+	RecordTypeREDIRECT_URL RecordType = "REDIRECT_URL"
+	RecordTypeRP           RecordType = "RP"
+	RecordTypeRRSIG        RecordType = "RRSIG"
+	RecordTypeSMIMEA       RecordType = "SMIMEA"
+	RecordTypeSOA          RecordType = "SOA"
+	RecordTypeSPF          RecordType = "SPF"
+	RecordTypeSRV          RecordType = "SRV"
+	RecordTypeSSHFP        RecordType = "SSHFP"
+	RecordTypeSVCB         RecordType = "SVCB"
+	RecordTypeTLSA         RecordType = "TLSA"
+	RecordTypeTXT          RecordType = "TXT"
+	RecordTypeURI          RecordType = "URI"
+	RecordTypeURL          RecordType = "URL"
+	RecordTypeWKS          RecordType = "WKS"
+	RecordTypeCAA          RecordType = "CAA"
+)
+
+// Deprecated: use RecordTypeRP.
+const RecordRP = RecordTypeRP
+
+// Record Status constants
+
+type RecordStatus string
+
+const (
+	RecordStatusENABLED  RecordStatus = "enabled"
+	RecordStatusDISABLED RecordStatus = "disabled"
+)
+
+// Record Line constants
+
+type RecordLine string
+
+const (
+	RecordLineDEFAULT   RecordLine = "default"
+	RecordLineTELECOM   RecordLine = "telecom"
+	RecordLineUNICOM    RecordLine = "unicom"
+	RecordLineMOBILE    RecordLine = "mobile"
+	RecordLineEDUCATION RecordLine = "education"
+	RecordLineOVERSEAS  RecordLine = "overseas"
+	RecordLineSEARCH    RecordLine = "search"
+
+	RecordLineBEIJING      RecordLine = "cn_beijing"
+	RecordLineTIANJIN      RecordLine = "cn_tianjin"
+	RecordLineHEBEI        RecordLine = "cn_hebei"
+	RecordLineSHANXI       RecordLine = "cn_shanxi"
+	RecordLineNEIMENGGU    RecordLine = "cn_neimenggu"
+	RecordLineLIAONING     RecordLine = "cn_liaoning"
+	RecordLineJILIN        RecordLine = "cn_jilin"
+	RecordLineHEILONGJIANG RecordLine = "cn_heilongjiang"
+	RecordLineSHANGHAI     RecordLine = "cn_shanghai"
+	RecordLineJIANGSU      RecordLine = "cn_jiangsu"
+	RecordLineZHEJIANG     RecordLine = "cn_zhejiang"
+	RecordLineANHUI        RecordLine = "cn_anhui"
+	RecordLineFUJIAN       RecordLine = "cn_fujian"
+	RecordLineJIANGXI      RecordLine = "cn_jiangxi"
+	RecordLineSHANDONG     RecordLine = "cn_shandong"
+	RecordLineHENAN        RecordLine = "cn_henan"
+	RecordLineHUBEI        RecordLine = "cn_hubei"
+	RecordLineHUNAN        RecordLine = "cn_hunan"
+	RecordLineGUANGDONG    RecordLine = "cn_guangdong"
+	RecordLineGUANGXI      RecordLine = "cn_guangxi"
+	RecordLineHAINAN       RecordLine = "cn_hainan"
+	RecordLineCHONGQING    RecordLine = "cn_chongqing"
+	RecordLineSICHUAN      RecordLine = "cn_sichuan"
+	RecordLineGUIZHOU      RecordLine = "cn_guizhou"
+	RecordLineYUNNAN       RecordLine = "cn_yunnan"
+	RecordLineXIZANG       RecordLine = "cn_xizang"
+	RecordLineSHAANXI      RecordLine = "cn_shaanxi"
+	RecordLineGANSU        RecordLine = "cn_gansu"
+	RecordLineQINGHAI      RecordLine = "cn_qinghai"
+	RecordLineNINGXIA      RecordLine = "cn_ningxia"
+	RecordLineXINJIANG     RecordLine = "cn_xinjiang"
+	RecordLineHONGKONG     RecordLine = "cn_hongkong"
+	RecordLineMACAO        RecordLine = "cn_macao"
+	RecordLineTAIWAN       RecordLine = "cn_taiwan"
+
+	RecordLineUS RecordLine = "os_us"
+	RecordLineCA RecordLine = "os_ca"
+	RecordLineGB RecordLine = "os_gb"
+	RecordLineDE RecordLine = "os_de"
+	RecordLineFR RecordLine = "os_fr"
+	RecordLineRU RecordLine = "os_ru"
+	RecordLineJP RecordLine = "os_jp"
+	RecordLineKR RecordLine = "os_kr"
+	RecordLineSG RecordLine = "os_sg"
+	RecordLineIN RecordLine = "os_in"
+	RecordLineAU RecordLine = "os_au"
+	RecordLineBR RecordLine = "os_br"
 )
 
 // Dns Error
@@ -52,10 +132,16 @@ const (
 type DnsError string
 
 const (
-	ZoneError                = "ZoneError"
-	ZoneDoesNotExistError    = "ZoneDoesNotExistError"
-	ZoneAlreadyExistsError   = "ZoneAlreadyExistsError"
-	RecordError              = "RecordError"
-	RecordDoesNotExistError  = "RecordDoesNotExistError"
-	RecordAlreadyExistsError = "RecordAlreadyExistsError"
+	ZoneError                DnsError = "ZoneError"
+	ZoneDoesNotExistError    DnsError = "ZoneDoesNotExistError"
+	ZoneAlreadyExistsError   DnsError = "ZoneAlreadyExistsError"
+	RecordError              DnsError = "RecordError"
+	RecordDoesNotExistError  DnsError = "RecordDoesNotExistError"
+	RecordAlreadyExistsError DnsError = "RecordAlreadyExistsError"
+	LineNotSupportedError    DnsError = "LineNotSupportedError"
+	NotSupportedError        DnsError = "NotSupportedError"
 )
+
+func (e DnsError) Error() string {
+	return string(e)
+}
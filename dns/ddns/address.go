@@ -0,0 +1,122 @@
+package ddns
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var defaultUrls = map[bool]string{
+	false: "https://api.ipify.org",
+	true:  "https://api6.ipify.org",
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Detect public address of source, ipv6 selects the address family
+func Detect(source *Source, ipv6 bool) (string, error) {
+
+	if source.Interface != "" {
+		return interfaceAddress(source.Interface, ipv6)
+	}
+
+	url := source.Url
+	if url == "" {
+		url = defaultUrls[ipv6]
+	}
+
+	return echoAddress(url, ipv6)
+
+}
+
+// 读取网卡地址，仅使用公网地址，私有地址无法从外部访问
+
+func interfaceAddress(name string, ipv6 bool) (string, error) {
+
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return "", err
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return "", err
+	}
+
+	ip := publicAddress(addrs, ipv6)
+	if ip == nil {
+		return "", fmt.Errorf("no public %s address on interface %s", family(ipv6), name)
+	}
+
+	return ip.String(), nil
+
+}
+
+// 选取第一个公网地址，跳过私有地址（RFC1918、ULA）
+
+func publicAddress(addrs []net.Addr, ipv6 bool) net.IP {
+
+	for _, addr := range addrs {
+		ipnet, ok := addr.(*net.IPNet)
+		if !ok || !ipnet.IP.IsGlobalUnicast() || ipnet.IP.IsPrivate() || isIPv6(ipnet.IP) != ipv6 {
+			continue
+		}
+		return ipnet.IP
+	}
+
+	return nil
+
+}
+
+// 从回显地址读取公网地址
+
+func echoAddress(url string, ipv6 bool) (string, error) {
+
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New("echo url returned " + resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return "", err
+	}
+
+	ip := net.ParseIP(strings.TrimSpace(string(body)))
+	if ip == nil || isIPv6(ip) != ipv6 {
+		return "", fmt.Errorf("echo url returned no %s address", family(ipv6))
+	}
+
+	return ip.String(), nil
+
+}
+
+// 检查是否为 IPv6 地址
+
+func isIPv6(ip net.IP) bool {
+
+	return ip.To4() == nil
+
+}
+
+// 地址族名称
+
+func family(ipv6 bool) string {
+
+	if ipv6 {
+		return "ipv6"
+	}
+
+	return "ipv4"
+
+}
@@ -0,0 +1,43 @@
+package ddns
+
+import (
+	"net"
+	"testing"
+)
+
+func TestPublicAddress(t *testing.T) {
+
+	addrs := func(cidrs ...string) []net.Addr {
+		list := []net.Addr{}
+		for _, cidr := range cidrs {
+			ip, ipnet, _ := net.ParseCIDR(cidr)
+			list = append(list, &net.IPNet{IP: ip, Mask: ipnet.Mask})
+		}
+		return list
+	}
+
+	tests := []struct {
+		name  string
+		addrs []net.Addr
+		ipv6  bool
+		want  string
+	}{
+		{"public ipv4", addrs("10.0.0.2/8", "203.0.113.7/24"), false, "203.0.113.7"},
+		{"private ipv4 only", addrs("127.0.0.1/8", "192.168.1.2/24", "172.16.0.2/12"), false, ""},
+		{"public ipv6", addrs("fe80::1/64", "fd00::1/64", "2001:db8::1/64"), true, "2001:db8::1"},
+		{"ula only", addrs("fd12:3456::1/64", "203.0.113.7/24"), true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if ip := publicAddress(tt.addrs, tt.ipv6); ip != nil {
+				got = ip.String()
+			}
+			if got != tt.want {
+				t.Errorf("publicAddress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+}
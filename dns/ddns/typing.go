@@ -0,0 +1,46 @@
+package ddns
+
+import (
+	"time"
+
+	"github.com/rehiy/cloudgo/dns"
+)
+
+// updater settings, shared by all providers
+
+type Config struct {
+	Zone      string         `json:"zone,omitempty" yaml:"zone,omitempty"` // discovered from hostnames when empty
+	Hostnames []string       `json:"hostnames" yaml:"hostnames"`
+	IPv4      *Source        `json:"ipv4,omitempty" yaml:"ipv4,omitempty"` // nil disables A records
+	IPv6      *Source        `json:"ipv6,omitempty" yaml:"ipv6,omitempty"` // nil disables AAAA records
+	Line      dns.RecordLine `json:"line,omitempty" yaml:"line,omitempty"`
+	TTL       int            `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	Interval  time.Duration  `json:"interval,omitempty" yaml:"interval,omitempty"`
+}
+
+// where to detect the public address, Interface wins over Url and must
+// carry a public address, the default echo url is used when both are empty
+
+type Source struct {
+	Interface string `json:"interface,omitempty" yaml:"interface,omitempty"`
+	Url       string `json:"url,omitempty" yaml:"url,omitempty"`
+}
+
+// change of a hostname, Old is empty for created records
+
+type Change struct {
+	Hostname string
+	Type     dns.RecordType
+	Old      string
+	New      string
+	Record   *dns.Record
+}
+
+// result of one run
+
+type Result struct {
+	Addresses map[dns.RecordType]string
+	Changed   []*Change
+	Unchanged []*Change
+	Errors    []error
+}
@@ -0,0 +1,246 @@
+package ddns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rehiy/cloudgo/dns"
+)
+
+const defaultInterval = 5 * time.Minute
+
+// keeps records of hostnames in sync with the public address
+
+type Updater struct {
+	provider dns.DnsProvider
+	config   *Config
+	last     map[dns.RecordType]string
+	zones    map[string]*dns.Zone
+}
+
+func NewUpdater(provider dns.DnsProvider, config *Config) *Updater {
+
+	return &Updater{provider, config, map[dns.RecordType]string{}, map[string]*dns.Zone{}}
+
+}
+
+// Detect addresses once and create or update records whose value changed,
+// the first error is returned, all errors are kept in result
+func (u *Updater) Run() (*Result, error) {
+
+	if len(u.config.Hostnames) == 0 || (u.config.IPv4 == nil && u.config.IPv6 == nil) {
+		return nil, errors.New("invalid ddns config")
+	}
+
+	result := &Result{Addresses: map[dns.RecordType]string{}}
+
+	sources := map[dns.RecordType]*Source{
+		dns.RecordTypeA:    u.config.IPv4,
+		dns.RecordTypeAAAA: u.config.IPv6,
+	}
+
+	for _, recordType := range []dns.RecordType{dns.RecordTypeA, dns.RecordTypeAAAA} {
+		source := sources[recordType]
+		if source == nil {
+			continue
+		}
+
+		address, err := Detect(source, recordType == dns.RecordTypeAAAA)
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+			continue
+		}
+
+		result.Addresses[recordType] = address
+	}
+
+	// 地址未变化时不调用接口
+	changed := map[dns.RecordType]string{}
+	for recordType, address := range result.Addresses {
+		if u.last[recordType] != address {
+			changed[recordType] = address
+		}
+	}
+
+	if len(changed) > 0 {
+		failed := u.sync(changed, result)
+		for recordType, address := range changed {
+			if !failed[recordType] {
+				u.last[recordType] = address
+			}
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		return result, result.Errors[0]
+	}
+
+	return result, nil
+
+}
+
+// Run at each interval until ctx is done, report receives the outcome of every run
+func (u *Updater) Loop(ctx context.Context, report func(*Result, error)) error {
+
+	interval := u.config.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := u.Run()
+		if report != nil {
+			report(result, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+}
+
+// 同步各主机名的记录，返回失败的记录类型
+
+func (u *Updater) sync(addresses map[dns.RecordType]string, result *Result) map[dns.RecordType]bool {
+
+	failed := map[dns.RecordType]bool{}
+	records := map[string][]*dns.Record{}
+
+	for _, hostname := range u.config.Hostnames {
+		zone, err := u.zone(hostname)
+		if err != nil {
+			result.Errors = append(result.Errors, err)
+			for recordType := range addresses {
+				failed[recordType] = true
+			}
+			continue
+		}
+
+		if _, ok := records[zone.Domain]; !ok {
+			list, err := u.provider.ListRecords(zone, nil)
+			if err != nil {
+				result.Errors = append(result.Errors, err)
+				for recordType := range addresses {
+					failed[recordType] = true
+				}
+				continue
+			}
+			records[zone.Domain] = list
+		}
+
+		name := zone.RelativeName(hostname)
+
+		for _, recordType := range []dns.RecordType{dns.RecordTypeA, dns.RecordTypeAAAA} {
+			address, ok := addresses[recordType]
+			if !ok {
+				continue
+			}
+
+			change, err := u.apply(zone, records[zone.Domain], name, recordType, address)
+			if err != nil {
+				failed[recordType] = true
+				result.Errors = append(result.Errors, fmt.Errorf("%s %s: %w", hostname, recordType, err))
+				continue
+			}
+
+			change.Hostname = hostname
+			if change.Old == change.New {
+				result.Unchanged = append(result.Unchanged, change)
+			} else {
+				result.Changed = append(result.Changed, change)
+			}
+		}
+	}
+
+	return failed
+
+}
+
+// 创建或更新单条记录
+
+func (u *Updater) apply(zone *dns.Zone, records []*dns.Record, name string, recordType dns.RecordType, address string) (*Change, error) {
+
+	ttl := u.config.TTL
+	change := &Change{Type: recordType, New: address}
+
+	for _, record := range records {
+		if record.Type != recordType || !strings.EqualFold(zone.RelativeName(record.Name), name) {
+			continue
+		}
+		if record.Line.IsDefault() != u.config.Line.IsDefault() || (!record.Line.IsDefault() && record.Line != u.config.Line) {
+			continue
+		}
+
+		change.Old, change.Record = record.Value, record
+		if record.Value == address && (ttl == 0 || record.TTL == ttl) {
+			return change, nil
+		}
+
+		data := *record
+		data.Value = address
+		if ttl > 0 {
+			data.TTL = ttl
+		}
+
+		updated, err := u.provider.UpdateRecord(zone, &data)
+		if err != nil {
+			return nil, err
+		}
+
+		*record = *updated
+		change.Record = updated
+		return change, nil
+	}
+
+	if ttl == 0 {
+		ttl = 600
+	}
+
+	created, err := u.provider.CreateRecord(zone, &dns.Record{
+		Name:  name,
+		Type:  recordType,
+		Value: address,
+		Line:  u.config.Line,
+		TTL:   ttl,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	change.Record = created
+	return change, nil
+
+}
+
+// 获取主机名所在区域，结果会被缓存
+
+func (u *Updater) zone(hostname string) (*dns.Zone, error) {
+
+	domain := hostname
+	if u.config.Zone != "" {
+		domain = u.config.Zone
+	}
+
+	if zone, ok := u.zones[domain]; ok {
+		return zone, nil
+	}
+
+	zone, err := dns.FindZone(u.provider, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	u.zones[domain] = zone
+	return zone, nil
+
+}
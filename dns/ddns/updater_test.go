@@ -0,0 +1,213 @@
+package ddns
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/rehiy/cloudgo/dns"
+)
+
+// 模拟平台，仅实现更新所需的方法
+type fakeProvider struct {
+	dns.DnsProvider
+	records []*dns.Record
+	calls   []string
+	nextId  int
+}
+
+func (p *fakeProvider) ListZones() ([]*dns.Zone, error) {
+
+	return []*dns.Zone{{Id: "z1", Domain: "example.com"}, {Id: "z2", Domain: "home.example.com"}}, nil
+
+}
+
+func (p *fakeProvider) ListRecords(zone *dns.Zone, filter *dns.RecordFilter) ([]*dns.Record, error) {
+
+	p.calls = append(p.calls, "list "+zone.Domain)
+
+	records := []*dns.Record{}
+	for _, record := range p.records {
+		if filter.Match(zone, record) {
+			item := *record
+			records = append(records, &item)
+		}
+	}
+
+	return records, nil
+
+}
+
+func (p *fakeProvider) CreateRecord(zone *dns.Zone, record *dns.Record) (*dns.Record, error) {
+
+	p.calls = append(p.calls, fmt.Sprintf("create %s %s %s", record.Name, record.Type, record.Value))
+	p.nextId++
+
+	item := *record
+	item.Id = fmt.Sprint(p.nextId)
+	p.records = append(p.records, &item)
+
+	created := item
+	return &created, nil
+
+}
+
+func (p *fakeProvider) UpdateRecord(zone *dns.Zone, record *dns.Record) (*dns.Record, error) {
+
+	p.calls = append(p.calls, fmt.Sprintf("update %s %s %s", record.Name, record.Type, record.Value))
+
+	for i, item := range p.records {
+		if item.Id == record.Id {
+			update := *record
+			p.records[i] = &update
+			return record, nil
+		}
+	}
+
+	return nil, dns.RecordDoesNotExistError
+
+}
+
+// 回显服务，返回当前设置的地址
+func echoServer(t *testing.T, address *string) string {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, *address)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server.URL
+
+}
+
+func TestUpdaterRun(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		config   Config
+		existing []*dns.Record
+		want     []string
+		changed  int
+	}{
+		{
+			name:    "create missing record",
+			config:  Config{Hostnames: []string{"nas.home.example.com"}},
+			want:    []string{"list home.example.com", "create nas A 203.0.113.7"},
+			changed: 1,
+		},
+		{
+			name:     "update changed address",
+			config:   Config{Hostnames: []string{"nas.home.example.com."}},
+			existing: []*dns.Record{{Id: "1", Name: "nas", Type: dns.RecordTypeA, Value: "203.0.113.1", TTL: 600}},
+			want:     []string{"list home.example.com", "update nas A 203.0.113.7"},
+			changed:  1,
+		},
+		{
+			name:     "unchanged address",
+			config:   Config{Hostnames: []string{"nas.home.example.com"}},
+			existing: []*dns.Record{{Id: "1", Name: "nas", Type: dns.RecordTypeA, Value: "203.0.113.7", TTL: 600}},
+			want:     []string{"list home.example.com"},
+		},
+		{
+			name:     "ttl change updates record",
+			config:   Config{Hostnames: []string{"nas.home.example.com"}, TTL: 60},
+			existing: []*dns.Record{{Id: "1", Name: "nas", Type: dns.RecordTypeA, Value: "203.0.113.7", TTL: 600}},
+			want:     []string{"list home.example.com", "update nas A 203.0.113.7"},
+		},
+		{
+			name:     "other line is left alone",
+			config:   Config{Hostnames: []string{"nas.home.example.com"}, Line: dns.RecordLineTELECOM},
+			existing: []*dns.Record{{Id: "1", Name: "nas", Type: dns.RecordTypeA, Value: "203.0.113.7", TTL: 600}},
+			want:     []string{"list home.example.com", "create nas A 203.0.113.7"},
+			changed:  1,
+		},
+		{
+			name:    "configured zone",
+			config:  Config{Zone: "example.com", Hostnames: []string{"nas.home.example.com", "example.com"}},
+			want:    []string{"list example.com", "create nas.home A 203.0.113.7", "create @ A 203.0.113.7"},
+			changed: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := "203.0.113.7"
+
+			config := tt.config
+			config.IPv4 = &Source{Url: echoServer(t, &address)}
+
+			provider := &fakeProvider{records: tt.existing}
+
+			result, err := NewUpdater(provider, &config).Run()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(provider.calls, tt.want) {
+				t.Errorf("calls = %q, want %q", provider.calls, tt.want)
+			}
+
+			if len(result.Changed) != tt.changed || result.Addresses[dns.RecordTypeA] != address {
+				t.Errorf("changed %d, addresses %v", len(result.Changed), result.Addresses)
+			}
+		})
+	}
+
+}
+
+func TestUpdaterRunSkipsSameAddress(t *testing.T) {
+
+	address := "203.0.113.7"
+
+	config := &Config{Hostnames: []string{"nas.example.com"}, IPv4: &Source{Url: echoServer(t, &address)}}
+	provider := &fakeProvider{}
+	updater := NewUpdater(provider, config)
+
+	for _, value := range []string{"203.0.113.7", "203.0.113.7", "203.0.113.8"} {
+		address = value
+		if _, err := updater.Run(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := []string{
+		"list example.com", "create nas A 203.0.113.7",
+		"list example.com", "update nas A 203.0.113.8",
+	}
+	if !reflect.DeepEqual(provider.calls, want) {
+		t.Errorf("calls = %q, want %q", provider.calls, want)
+	}
+
+}
+
+func TestUpdaterRunErrors(t *testing.T) {
+
+	address := "2001:db8::1"
+	url := echoServer(t, &address)
+
+	tests := []struct {
+		name   string
+		config *Config
+	}{
+		{"no hostnames", &Config{IPv4: &Source{Url: url}}},
+		{"no sources", &Config{Hostnames: []string{"nas.example.com"}}},
+		{"wrong address family", &Config{Hostnames: []string{"nas.example.com"}, IPv4: &Source{Url: url}}},
+		{"zone not found", &Config{Hostnames: []string{"nas.example.org"}, IPv6: &Source{Url: url}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &fakeProvider{}
+			if _, err := NewUpdater(provider, tt.config).Run(); err == nil {
+				t.Error("want error")
+			}
+			if len(provider.records) != 0 {
+				t.Errorf("records = %v, want none", provider.records)
+			}
+		})
+	}
+
+}
@@ -1,6 +1,8 @@
 package drivers
 
 import (
+	"fmt"
+
 	"github.com/rehiy/cloudgo/dns"
 	"github.com/rehiy/cloudgo/provider"
 	"github.com/rehiy/cloudgo/provider/alibaba"
@@ -9,6 +11,8 @@ import (
 	"github.com/alibabacloud-go/tea/tea"
 )
 
+var _ dns.DnsProvider = (*AlibabaAlidnsDriver)(nil)
+
 type AlibabaAlidnsDriver struct {
 	client *alibaba.Client
 	alidns *alidns.Client
@@ -26,27 +30,42 @@ func NewAlibabaAlidnsDriver(rq *provider.ReqeustParam) *AlibabaAlidnsDriver {
 
 func (p *AlibabaAlidnsDriver) ListZones() ([]*dns.Zone, error) {
 
-	resp, err := p.alidns.DescribeDomains(&alidns.DescribeDomainsRequest{})
-
-	if err != nil {
-		return nil, err
+	req := &alidns.DescribeDomainsRequest{
+		PageSize: tea.Int64(100),
 	}
 
 	zones := make([]*dns.Zone, 0)
 
-	for _, domain := range resp.Body.Domains.Domain {
-		dnsServers := make([]string, 0)
-		for _, dnsServer := range domain.DnsServers.DnsServer {
-			dnsServers = append(dnsServers, *dnsServer)
+	for page, count := int64(1), int64(0); ; page++ {
+		req.PageNumber = tea.Int64(page)
+
+		resp, err := p.alidns.DescribeDomains(req)
+
+		if err != nil {
+			return nil, err
 		}
 
-		zones = append(zones, &dns.Zone{
-			Id:         *domain.DomainId,
-			Domain:     *domain.DomainName,
-			PunyCode:   *domain.PunyCode,
-			DnsServers: dnsServers,
-			CreateTime: int(*domain.CreateTimestamp),
-		})
+		for _, domain := range resp.Body.Domains.Domain {
+			dnsServers := make([]string, 0)
+			if domain.DnsServers != nil {
+				for _, dnsServer := range domain.DnsServers.DnsServer {
+					dnsServers = append(dnsServers, *dnsServer)
+				}
+			}
+
+			zones = append(zones, &dns.Zone{
+				Id:         *domain.DomainId,
+				Domain:     *domain.DomainName,
+				PunyCode:   tea.StringValue(domain.PunyCode),
+				DnsServers: dnsServers,
+				CreateTime: int(tea.Int64Value(domain.CreateTimestamp)),
+			})
+		}
+
+		count += int64(len(resp.Body.Domains.Domain))
+		if len(resp.Body.Domains.Domain) == 0 || count >= tea.Int64Value(resp.Body.TotalCount) {
+			break
+		}
 	}
 
 	return zones, nil
@@ -125,29 +144,86 @@ func (p *AlibabaAlidnsDriver) DeleteZone(zone *dns.Zone) error {
 
 }
 
-func (p *AlibabaAlidnsDriver) ListRecords(zone *dns.Zone) ([]*dns.Record, error) {
+func (p *AlibabaAlidnsDriver) ListRecords(zone *dns.Zone, filter *dns.RecordFilter) ([]*dns.Record, error) {
 
-	resp, err := p.alidns.DescribeDomainRecords(&alidns.DescribeDomainRecordsRequest{
+	req := &alidns.DescribeDomainRecordsRequest{
 		DomainName: tea.String(zone.Domain),
-	})
+		PageSize:   tea.Int64(500),
+	}
 
-	if err != nil {
-		return nil, err
+	if filter == nil {
+		filter = &dns.RecordFilter{}
+	}
+
+	// 高级模式下按主机记录、类型和记录值模糊搜索
+	if filter.Name != "" || filter.Keyword != "" || filter.Value != "" {
+		req.SearchMode = tea.String("ADVANCED")
+		if filter.Name != "" {
+			req.RRKeyWord = tea.String(zone.RelativeName(filter.Name))
+		} else if filter.Keyword != "" {
+			req.RRKeyWord = tea.String(filter.Keyword)
+		}
+		if filter.Value != "" {
+			req.ValueKeyWord = tea.String(filter.Value)
+		}
+		if filter.Type != "" {
+			req.TypeKeyWord = tea.String(string(filter.Type))
+		}
+	} else if filter.Type != "" {
+		req.Type = tea.String(string(filter.Type))
+	}
+
+	if code, ok := alidnsRecordLines[filter.Line]; ok {
+		req.Line = tea.String(code)
+	}
+
+	switch filter.Status {
+	case dns.RecordStatusENABLED:
+		req.Status = tea.String("Enable")
+	case dns.RecordStatusDISABLED:
+		req.Status = tea.String("Disable")
 	}
 
 	records := make([]*dns.Record, 0)
+	codes := map[string]dns.RecordLine{}
 
-	for _, record := range resp.Body.DomainRecords.Record {
-		recordType := dns.RecordType(*record.Type)
+	for page, count := int64(1), int64(0); ; page++ {
+		req.PageNumber = tea.Int64(page)
 
-		records = append(records, &dns.Record{
-			Id:       *record.RecordId,
-			Name:     *record.RR,
-			Type:     recordType,
-			Value:    *record.Value,
-			TTL:      int(*record.TTL),
-			Priority: int(*record.Priority),
-		})
+		resp, err := p.alidns.DescribeDomainRecords(req)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range resp.Body.DomainRecords.Record {
+			line, err := p.recordLineOf(zone, *record.Line, codes)
+			if err != nil {
+				return nil, err
+			}
+
+			data := &dns.Record{
+				Id:          *record.RecordId,
+				Name:        *record.RR,
+				Type:        dns.RecordType(*record.Type),
+				Value:       *record.Value,
+				Line:        line,
+				TTL:         int(*record.TTL),
+				Priority:    int(tea.Int64Value(record.Priority)),
+				Weight:      int(tea.Int32Value(record.Weight)),
+				Status:      alidnsRecordStatus(tea.StringValue(record.Status)),
+				Description: tea.StringValue(record.Remark),
+			}
+
+			if filter.Match(zone, data) {
+				records = append(records, data)
+			}
+		}
+
+		count += int64(len(resp.Body.DomainRecords.Record))
+		if len(resp.Body.DomainRecords.Record) == 0 || count >= tea.Int64Value(resp.Body.TotalCount) {
+			break
+		}
 	}
 
 	return records, nil
@@ -166,55 +242,134 @@ func (p *AlibabaAlidnsDriver) DetailRecord(zone *dns.Zone, record *dns.Record) (
 
 	recordType := dns.RecordType(*resp.Body.Type)
 
-	record = &dns.Record{
-		Id:    *resp.Body.RecordId,
-		Name:  *resp.Body.RR,
-		Type:  recordType,
-		Value: *resp.Body.Value,
-		TTL:   int(*resp.Body.TTL),
+	line, err := p.recordLineOf(zone, *resp.Body.Line, map[string]dns.RecordLine{})
+	if err != nil {
+		return nil, err
+	}
+
+	data := &dns.Record{
+		Id:          *resp.Body.RecordId,
+		Name:        *resp.Body.RR,
+		Type:        recordType,
+		Value:       *resp.Body.Value,
+		Line:        line,
+		TTL:         int(*resp.Body.TTL),
+		Priority:    int(tea.Int64Value(resp.Body.Priority)),
+		Status:      alidnsRecordStatus(tea.StringValue(resp.Body.Status)),
+		Description: tea.StringValue(resp.Body.Remark),
+	}
+
+	// 记录详情不含权重，从子域名记录列表读取
+	if data.Weight, err = p.recordWeight(zone, data); err != nil {
+		return nil, err
 	}
 
-	return record, nil
+	return data, nil
 
 }
 
 func (p *AlibabaAlidnsDriver) CreateRecord(zone *dns.Zone, record *dns.Record) (*dns.Record, error) {
 
+	recordLine, err := p.recordLine(zone, record.Line)
+	if err != nil {
+		return nil, err
+	}
+
 	resp, err := p.alidns.AddDomainRecord(&alidns.AddDomainRecordRequest{
 		DomainName: tea.String(zone.Domain),
 		RR:         tea.String(record.Name),
 		Type:       tea.String(string(record.Type)),
 		Value:      tea.String(record.Value),
+		Line:       tea.String(recordLine),
 		TTL:        tea.Int64(int64(record.TTL)),
+		Priority:   alidnsPriority(record),
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	record = &dns.Record{
-		Id: *resp.Body.RecordId,
+	data := *record
+	data.Id = *resp.Body.RecordId
+
+	// 创建接口不支持状态和备注，创建后单独设置
+	if record.Status == dns.RecordStatusDISABLED {
+		if err := p.SetRecordStatus(zone, &data, record.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	if record.Description != "" {
+		_, err = p.alidns.UpdateDomainRecordRemark(&alidns.UpdateDomainRecordRemarkRequest{
+			RecordId: tea.String(data.Id),
+			Remark:   tea.String(record.Description),
+		})
+
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return record, nil
+	// 权重需通过 SetRecordWeight 开启后设置
+	data.Weight = 0
+
+	return &data, nil
 
 }
 
 func (p *AlibabaAlidnsDriver) UpdateRecord(zone *dns.Zone, record *dns.Record) (*dns.Record, error) {
 
-	_, err := p.alidns.UpdateDomainRecord(&alidns.UpdateDomainRecordRequest{
+	recordLine, err := p.recordLine(zone, record.Line)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := p.DetailRecord(zone, record)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.alidns.UpdateDomainRecord(&alidns.UpdateDomainRecordRequest{
 		RecordId: tea.String(record.Id),
 		RR:       tea.String(record.Name),
 		Type:     tea.String(string(record.Type)),
 		Value:    tea.String(record.Value),
+		Line:     tea.String(recordLine),
 		TTL:      tea.Int64(int64(record.TTL)),
+		Priority: alidnsPriority(record),
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return record, nil
+	data := *record
+
+	// 修改接口不支持状态、备注和权重，与当前记录不同时单独设置
+	if record.Status != "" && record.Status != current.Status {
+		if err := p.SetRecordStatus(zone, &data, record.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	if record.Description != current.Description {
+		_, err = p.alidns.UpdateDomainRecordRemark(&alidns.UpdateDomainRecordRemarkRequest{
+			RecordId: tea.String(data.Id),
+			Remark:   tea.String(record.Description),
+		})
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if record.Weight > 0 && record.Weight != current.Weight {
+		if err := p.SetRecordWeight(zone, &data, record.Weight); err != nil {
+			return nil, err
+		}
+	}
+
+	return p.DetailRecord(zone, &data)
 
 }
 
@@ -227,3 +382,239 @@ func (p *AlibabaAlidnsDriver) DeleteRecord(zone *dns.Zone, record *dns.Record) e
 	return err
 
 }
+
+func (p *AlibabaAlidnsDriver) ListRecordTypes() ([]dns.RecordType, error) {
+
+	types := []dns.RecordType{
+		dns.RecordTypeA,
+		dns.RecordTypeAAAA,
+		dns.RecordTypeCNAME,
+		dns.RecordTypeMX,
+		dns.RecordTypeTXT,
+		dns.RecordTypeNS,
+		dns.RecordTypeSRV,
+		dns.RecordTypeCAA,
+		dns.RecordTypeREDIRECT_URL,
+		dns.RecordTypeFORWARD_URL,
+	}
+
+	return types, nil
+
+}
+
+func (p *AlibabaAlidnsDriver) ListRecordLines(zone *dns.Zone) ([]*dns.Line, error) {
+
+	resp, err := p.alidns.DescribeSupportLines(&alidns.DescribeSupportLinesRequest{
+		DomainName: tea.String(zone.Domain),
+		Lang:       tea.String("zh"),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]*dns.Line, 0)
+
+	for _, line := range resp.Body.RecordLines.RecordLine {
+		// 省份及国家线路按名称识别
+		code := alidnsRecordLineOf(*line.LineCode)
+		if parsed := dns.ParseRecordLine(*line.LineName); parsed.Label() != string(parsed) {
+			code = parsed
+		}
+
+		lines = append(lines, &dns.Line{
+			Id:     *line.LineCode,
+			Code:   code,
+			Name:   *line.LineName,
+			Parent: tea.StringValue(line.FatherCode),
+		})
+	}
+
+	return lines, nil
+
+}
+
+// 转换为阿里云线路代码，并检查版本是否支持
+
+func (p *AlibabaAlidnsDriver) recordLine(zone *dns.Zone, line dns.RecordLine) (string, error) {
+
+	if line.IsDefault() {
+		return alidnsRecordLines[dns.RecordLineDEFAULT], nil
+	}
+
+	lines, err := p.ListRecordLines(zone)
+	if err != nil {
+		return "", err
+	}
+
+	for _, item := range lines {
+		if item.Code == line || item.Id == string(line) {
+			return item.Id, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", dns.LineNotSupportedError, line)
+
+}
+
+// 转换阿里云线路代码，省份及国家线路按支持的线路识别，codes 为空时查询并填充
+
+func (p *AlibabaAlidnsDriver) recordLineOf(zone *dns.Zone, code string, codes map[string]dns.RecordLine) (dns.RecordLine, error) {
+
+	line := alidnsRecordLineOf(code)
+	if _, ok := alidnsRecordLines[line]; ok {
+		return line, nil
+	}
+
+	if len(codes) == 0 {
+		lines, err := p.ListRecordLines(zone)
+		if err != nil {
+			return "", err
+		}
+		for _, item := range lines {
+			codes[item.Id] = item.Code
+		}
+	}
+
+	if parsed, ok := codes[code]; ok {
+		return parsed, nil
+	}
+
+	return line, nil
+
+}
+
+// 读取记录权重，未开启权重时为 0
+
+func (p *AlibabaAlidnsDriver) recordWeight(zone *dns.Zone, record *dns.Record) (int, error) {
+
+	req := &alidns.DescribeSubDomainRecordsRequest{
+		DomainName: tea.String(zone.Domain),
+		SubDomain:  tea.String(zone.RelativeName(record.Name) + "." + zone.Domain),
+		Type:       tea.String(string(record.Type)),
+		PageSize:   tea.Int64(500),
+	}
+
+	for page, count := int64(1), int64(0); ; page++ {
+		req.PageNumber = tea.Int64(page)
+
+		resp, err := p.alidns.DescribeSubDomainRecords(req)
+
+		if err != nil {
+			return 0, err
+		}
+
+		for _, item := range resp.Body.DomainRecords.Record {
+			if tea.StringValue(item.RecordId) == record.Id {
+				return int(tea.Int32Value(item.Weight)), nil
+			}
+		}
+
+		count += int64(len(resp.Body.DomainRecords.Record))
+		if len(resp.Body.DomainRecords.Record) == 0 || count >= tea.Int64Value(resp.Body.TotalCount) {
+			break
+		}
+	}
+
+	return 0, nil
+
+}
+
+func (p *AlibabaAlidnsDriver) SetRecordStatus(zone *dns.Zone, record *dns.Record, status dns.RecordStatus) error {
+
+	value := "Enable"
+	if status == dns.RecordStatusDISABLED {
+		value = "Disable"
+	}
+
+	_, err := p.alidns.SetDomainRecordStatus(&alidns.SetDomainRecordStatusRequest{
+		RecordId: tea.String(record.Id),
+		Status:   tea.String(value),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	record.Status = status
+	return nil
+
+}
+
+func (p *AlibabaAlidnsDriver) SetRecordWeight(zone *dns.Zone, record *dns.Record, weight int) error {
+
+	// 先为子域名开启权重配置
+	_, err := p.alidns.SetDNSSLBStatus(&alidns.SetDNSSLBStatusRequest{
+		DomainName: tea.String(zone.Domain),
+		SubDomain:  tea.String(zone.RelativeName(record.Name) + "." + zone.Domain),
+		Type:       tea.String(string(record.Type)),
+		Open:       tea.Bool(true),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = p.alidns.UpdateDNSSLBWeight(&alidns.UpdateDNSSLBWeightRequest{
+		RecordId: tea.String(record.Id),
+		Weight:   tea.Int32(int32(weight)),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	record.Weight = weight
+	return nil
+
+}
+
+// 转换阿里云记录状态
+
+func alidnsRecordStatus(status string) dns.RecordStatus {
+
+	if status == "DISABLE" || status == "Disable" {
+		return dns.RecordStatusDISABLED
+	}
+
+	return dns.RecordStatusENABLED
+
+}
+
+// 转换记录优先级，仅 MX 记录有效
+
+func alidnsPriority(record *dns.Record) *int64 {
+
+	if record.Type != dns.RecordTypeMX {
+		return nil
+	}
+
+	return tea.Int64(int64(record.Priority))
+
+}
+
+// 线路代码映射
+
+var alidnsRecordLines = map[dns.RecordLine]string{
+	dns.RecordLineDEFAULT:   "default",
+	dns.RecordLineTELECOM:   "telecom",
+	dns.RecordLineUNICOM:    "unicom",
+	dns.RecordLineMOBILE:    "mobile",
+	dns.RecordLineEDUCATION: "edu",
+	dns.RecordLineOVERSEAS:  "oversea",
+	dns.RecordLineSEARCH:    "search",
+}
+
+// 转换阿里云线路代码
+
+func alidnsRecordLineOf(code string) dns.RecordLine {
+
+	for line, v := range alidnsRecordLines {
+		if v == code {
+			return line
+		}
+	}
+
+	return dns.RecordLine(code)
+
+}
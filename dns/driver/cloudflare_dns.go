@@ -1,6 +1,9 @@
 package drivers
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/rehiy/cloudgo/dns"
 	"github.com/rehiy/cloudgo/provider"
 	"github.com/rehiy/cloudgo/provider/cloudflare"
@@ -8,6 +11,8 @@ import (
 	cf "github.com/cloudflare/cloudflare-go"
 )
 
+var _ dns.DnsProvider = (*CloudflareDnsDriver)(nil)
+
 type CloudflareDnsDriver struct {
 	client *cloudflare.Client
 	api    *cf.API
@@ -71,7 +76,12 @@ func (p *CloudflareDnsDriver) DetailZone(zone *dns.Zone) (*dns.Zone, error) {
 
 func (p *CloudflareDnsDriver) CreateZone(zone *dns.Zone) (*dns.Zone, error) {
 
-	account := cf.Account{ID: ""}
+	// 创建区域必须指定所属账号
+	if p.rq.AccountId == "" {
+		return nil, fmt.Errorf("%w: account id required to create zone %s", dns.NotSupportedError, zone.Domain)
+	}
+
+	account := cf.Account{ID: p.rq.AccountId}
 
 	resp, err := p.api.CreateZone(p.client.Ctx, zone.Domain, false, account, "full")
 
@@ -91,13 +101,7 @@ func (p *CloudflareDnsDriver) CreateZone(zone *dns.Zone) (*dns.Zone, error) {
 
 func (p *CloudflareDnsDriver) UpdateZone(zone *dns.Zone) (*dns.Zone, error) {
 
-	_, err := p.api.ZoneDetails(p.client.Ctx, zone.Id)
-
-	if err != nil {
-		return nil, err
-	}
-
-	return zone, nil
+	return p.DetailZone(zone)
 
 }
 
@@ -113,31 +117,45 @@ func (p *CloudflareDnsDriver) DeleteZone(zone *dns.Zone) error {
 
 }
 
-func (p *CloudflareDnsDriver) ListRecords(zone *dns.Zone) ([]*dns.Record, error) {
+func (p *CloudflareDnsDriver) ListRecords(zone *dns.Zone, filter *dns.RecordFilter) ([]*dns.Record, error) {
 
 	rc := &cf.ResourceContainer{
 		Identifier: zone.Id,
 	}
 
-	resp, _, err := p.api.ListDNSRecords(p.client.Ctx, rc, cf.ListDNSRecordsParams{})
+	params := cf.ListDNSRecordsParams{}
+	records := make([]*dns.Record, 0)
+
+	if filter == nil {
+		filter = &dns.RecordFilter{}
+	}
+
+	// 不支持线路和暂停解析
+	if !filter.Line.IsDefault() || filter.Status == dns.RecordStatusDISABLED {
+		return records, nil
+	}
+
+	if filter.Name != "" {
+		params.Name = strings.TrimSuffix(zone.AbsoluteName(filter.Name), ".")
+	}
+	if filter.Type != "" {
+		params.Type = string(filter.Type)
+	}
+	if filter.Value != "" {
+		params.Content = filter.Value
+	}
+
+	resp, _, err := p.api.ListDNSRecords(p.client.Ctx, rc, params)
 
 	if err != nil {
 		return nil, err
 	}
 
-	records := make([]*dns.Record, 0)
-
 	for _, record := range resp {
-		recordType := dns.RecordType(record.Type)
-
-		records = append(records, &dns.Record{
-			Id:       record.ID,
-			Name:     record.Name,
-			Type:     recordType,
-			Value:    record.Content,
-			TTL:      record.TTL,
-			Priority: int(*record.Priority),
-		})
+		data := cloudflareRecord(record)
+		if filter.Match(zone, data) {
+			records = append(records, data)
+		}
 	}
 
 	return records, nil
@@ -156,59 +174,62 @@ func (p *CloudflareDnsDriver) DetailRecord(zone *dns.Zone, record *dns.Record) (
 		return nil, err
 	}
 
-	recordType := dns.RecordType(resp.Type)
-
-	data := &dns.Record{
-		Id:    resp.ID,
-		Name:  resp.Name,
-		Type:  recordType,
-		Value: resp.Content,
-		TTL:   resp.TTL,
-	}
-
-	return data, nil
+	return cloudflareRecord(resp), nil
 
 }
 
 func (p *CloudflareDnsDriver) CreateRecord(zone *dns.Zone, record *dns.Record) (*dns.Record, error) {
 
+	if !record.Line.IsDefault() {
+		return nil, fmt.Errorf("%w: %s", dns.LineNotSupportedError, record.Line)
+	}
+
 	rc := &cf.ResourceContainer{
 		Identifier: zone.Id,
 	}
 
-	_, err := p.api.CreateDNSRecord(p.client.Ctx, rc, cf.CreateDNSRecordParams{
-		Type:    string(record.Type),
-		Name:    record.Name,
-		Content: record.Value,
-		TTL:     record.TTL,
+	resp, err := p.api.CreateDNSRecord(p.client.Ctx, rc, cf.CreateDNSRecordParams{
+		Type:     string(record.Type),
+		Name:     record.Name,
+		Content:  record.Value,
+		TTL:      record.TTL,
+		Priority: cloudflarePriority(record),
+		Comment:  record.Description,
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return record, nil
+	return cloudflareRecord(resp), nil
 
 }
 
 func (p *CloudflareDnsDriver) UpdateRecord(zone *dns.Zone, record *dns.Record) (*dns.Record, error) {
 
+	if !record.Line.IsDefault() {
+		return nil, fmt.Errorf("%w: %s", dns.LineNotSupportedError, record.Line)
+	}
+
 	rc := &cf.ResourceContainer{
 		Identifier: zone.Id,
 	}
 
-	_, err := p.api.UpdateDNSRecord(p.client.Ctx, rc, cf.UpdateDNSRecordParams{
-		Type:    string(record.Type),
-		Name:    record.Name,
-		Content: record.Value,
-		TTL:     record.TTL,
+	resp, err := p.api.UpdateDNSRecord(p.client.Ctx, rc, cf.UpdateDNSRecordParams{
+		ID:       record.Id,
+		Type:     string(record.Type),
+		Name:     record.Name,
+		Content:  record.Value,
+		TTL:      record.TTL,
+		Priority: cloudflarePriority(record),
+		Comment:  record.Description,
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return record, nil
+	return cloudflareRecord(resp), nil
 
 }
 
@@ -223,3 +244,93 @@ func (p *CloudflareDnsDriver) DeleteRecord(zone *dns.Zone, record *dns.Record) e
 	return err
 
 }
+
+func (p *CloudflareDnsDriver) SetRecordStatus(zone *dns.Zone, record *dns.Record, status dns.RecordStatus) error {
+
+	return fmt.Errorf("%w: cloudflare records can't be disabled", dns.NotSupportedError)
+
+}
+
+func (p *CloudflareDnsDriver) SetRecordWeight(zone *dns.Zone, record *dns.Record, weight int) error {
+
+	return fmt.Errorf("%w: cloudflare records have no weight", dns.NotSupportedError)
+
+}
+
+func (p *CloudflareDnsDriver) ListRecordTypes() ([]dns.RecordType, error) {
+
+	types := []dns.RecordType{
+		dns.RecordTypeA,
+		dns.RecordTypeAAAA,
+		dns.RecordTypeCAA,
+		dns.RecordTypeCERT,
+		dns.RecordTypeCNAME,
+		dns.RecordTypeDNSKEY,
+		dns.RecordTypeDS,
+		dns.RecordTypeHTTPS,
+		dns.RecordTypeLOC,
+		dns.RecordTypeMX,
+		dns.RecordTypeNAPTR,
+		dns.RecordTypeNS,
+		dns.RecordTypePTR,
+		dns.RecordTypeSMIMEA,
+		dns.RecordTypeSRV,
+		dns.RecordTypeSSHFP,
+		dns.RecordTypeSVCB,
+		dns.RecordTypeTLSA,
+		dns.RecordTypeTXT,
+		dns.RecordTypeURI,
+	}
+
+	return types, nil
+
+}
+
+func (p *CloudflareDnsDriver) ListRecordLines(zone *dns.Zone) ([]*dns.Line, error) {
+
+	lines := []*dns.Line{
+		{
+			Id:   string(dns.RecordLineDEFAULT),
+			Code: dns.RecordLineDEFAULT,
+			Name: dns.RecordLineDEFAULT.Label(),
+		},
+	}
+
+	return lines, nil
+
+}
+
+// 转换记录
+
+func cloudflareRecord(record cf.DNSRecord) *dns.Record {
+
+	data := &dns.Record{
+		Id:          record.ID,
+		Name:        record.Name,
+		Type:        dns.RecordType(record.Type),
+		Value:       record.Content,
+		TTL:         record.TTL,
+		Status:      dns.RecordStatusENABLED,
+		Description: record.Comment,
+	}
+
+	if record.Priority != nil {
+		data.Priority = int(*record.Priority)
+	}
+
+	return data
+
+}
+
+// 转换记录优先级
+
+func cloudflarePriority(record *dns.Record) *uint16 {
+
+	if record.Type != dns.RecordTypeMX && record.Type != dns.RecordTypeURI {
+		return nil
+	}
+
+	priority := uint16(record.Priority)
+	return &priority
+
+}
@@ -1,15 +1,19 @@
 package drivers
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/rehiy/cloudgo/dns"
 	"github.com/rehiy/cloudgo/provider"
 	"github.com/rehiy/cloudgo/provider/tencent"
 
+	tc "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
 	dnspod "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/dnspod/v20210323"
 )
 
+var _ dns.DnsProvider = (*TecentDnspodDriver)(nil)
+
 type TecentDnspodDriver struct {
 	client *tencent.Client
 	dnspod *dnspod.Client
@@ -101,7 +105,7 @@ func (p *TecentDnspodDriver) CreateZone(zone *dns.Zone) (*dns.Zone, error) {
 		Id:         strconv.Itoa(int(*reps.Response.DomainInfo.Id)),
 		Domain:     *reps.Response.DomainInfo.Domain,
 		PunyCode:   *reps.Response.DomainInfo.Punycode,
-		DnsServers: []string{},
+		DnsServers: dnsServers,
 	}
 
 	return data, nil
@@ -125,7 +129,7 @@ func (p *TecentDnspodDriver) UpdateZone(zone *dns.Zone) (*dns.Zone, error) {
 
 func (p *TecentDnspodDriver) DeleteZone(zone *dns.Zone) error {
 
-	_, err := p.dnspod.DescribeDomain(&dnspod.DescribeDomainRequest{
+	_, err := p.dnspod.DeleteDomain(&dnspod.DeleteDomainRequest{
 		Domain: &zone.Domain,
 	})
 
@@ -133,29 +137,70 @@ func (p *TecentDnspodDriver) DeleteZone(zone *dns.Zone) error {
 
 }
 
-func (p *TecentDnspodDriver) ListRecords(zone *dns.Zone) ([]*dns.Record, error) {
+func (p *TecentDnspodDriver) ListRecords(zone *dns.Zone, filter *dns.RecordFilter) ([]*dns.Record, error) {
 
-	resp, err := p.dnspod.DescribeRecordList(&dnspod.DescribeRecordListRequest{
+	req := &dnspod.DescribeRecordListRequest{
 		Domain: &zone.Domain,
-	})
+		Limit:  tc.Uint64Ptr(3000),
+	}
 
-	if err != nil {
-		return nil, err
+	if filter == nil {
+		filter = &dns.RecordFilter{}
+	}
+
+	if filter.Name != "" {
+		req.Subdomain = tc.StringPtr(zone.RelativeName(filter.Name))
+	}
+	if filter.Type != "" {
+		req.RecordType = tc.StringPtr(dnspodRecordType(filter.Type))
+	}
+	if filter.Line != "" {
+		req.RecordLine = tc.StringPtr(filter.Line.Label())
+	}
+	if filter.Keyword != "" {
+		req.Keyword = &filter.Keyword
+	} else if filter.Value != "" {
+		req.Keyword = &filter.Value
 	}
 
 	records := make([]*dns.Record, 0)
 
-	for _, record := range resp.Response.RecordList {
-		recordType := dns.RecordType(*record.Type)
+	for offset := uint64(0); ; {
+		req.Offset = &offset
 
-		records = append(records, &dns.Record{
-			Id:       strconv.Itoa(int(*record.RecordId)),
-			Name:     *record.Name,
-			Type:     recordType,
-			Value:    *record.Value,
-			TTL:      int(*record.TTL),
-			Priority: int(*record.MX),
-		})
+		resp, err := p.dnspod.DescribeRecordList(req)
+
+		if err != nil {
+			// 没有匹配记录时返回错误
+			if p.client.Error(err).Code == "ResourceNotFound.NoDataOfRecord" {
+				break
+			}
+			return nil, err
+		}
+
+		for _, record := range resp.Response.RecordList {
+			data := &dns.Record{
+				Id:          strconv.Itoa(int(*record.RecordId)),
+				Name:        *record.Name,
+				Type:        dnspodRecordTypeOf(*record.Type),
+				Value:       *record.Value,
+				Line:        dns.ParseRecordLine(*record.Line),
+				TTL:         int(*record.TTL),
+				Priority:    int(*record.MX),
+				Weight:      int(dnspodUint64(record.Weight)),
+				Status:      dnspodRecordStatus(*record.Status),
+				Description: *record.Remark,
+			}
+
+			if filter.Match(zone, data) {
+				records = append(records, data)
+			}
+		}
+
+		offset += uint64(len(resp.Response.RecordList))
+		if len(resp.Response.RecordList) == 0 || offset >= *resp.Response.RecordCountInfo.TotalCount {
+			break
+		}
 	}
 
 	return records, nil
@@ -176,15 +221,23 @@ func (p *TecentDnspodDriver) DetailRecord(zone *dns.Zone, record *dns.Record) (*
 		return nil, err
 	}
 
-	recordType := dns.RecordType(*resp.Response.RecordInfo.RecordType)
+	recordType := dnspodRecordTypeOf(*resp.Response.RecordInfo.RecordType)
 
 	data := &dns.Record{
-		Id:       strconv.Itoa(int(*resp.Response.RecordInfo.Id)),
-		Name:     *resp.Response.RecordInfo.SubDomain,
-		Type:     recordType,
-		Value:    *resp.Response.RecordInfo.Value,
-		TTL:      int(*resp.Response.RecordInfo.TTL),
-		Priority: int(*resp.Response.RecordInfo.MX),
+		Id:          strconv.Itoa(int(*resp.Response.RecordInfo.Id)),
+		Name:        *resp.Response.RecordInfo.SubDomain,
+		Type:        recordType,
+		Value:       *resp.Response.RecordInfo.Value,
+		Line:        dns.ParseRecordLine(*resp.Response.RecordInfo.RecordLine),
+		TTL:         int(*resp.Response.RecordInfo.TTL),
+		Priority:    int(*resp.Response.RecordInfo.MX),
+		Weight:      int(dnspodUint64(resp.Response.RecordInfo.Weight)),
+		Status:      dns.RecordStatusENABLED,
+		Description: *resp.Response.RecordInfo.Remark,
+	}
+
+	if dnspodUint64(resp.Response.RecordInfo.Enabled) == 0 {
+		data.Status = dns.RecordStatusDISABLED
 	}
 
 	return data, nil
@@ -195,53 +248,78 @@ func (p *TecentDnspodDriver) CreateRecord(zone *dns.Zone, record *dns.Record) (*
 
 	ttl := uint64(record.TTL)
 	priority := uint64(record.Priority)
+	recordType := dnspodRecordType(record.Type)
+
+	recordLine, err := p.recordLine(zone, record.Line)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := p.dnspod.CreateRecord(&dnspod.CreateRecordRequest{
 		Domain:     &zone.Domain,
 		SubDomain:  &record.Name,
-		RecordType: (*string)(&record.Type),
-		RecordLine: &record.Line,
+		RecordType: &recordType,
+		RecordLine: &recordLine,
 		Value:      &record.Value,
 		TTL:        &ttl,
 		MX:         &priority,
+		Weight:     dnspodWeight(record),
+		Status:     dnspodStatus(record.Status),
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	data := &dns.Record{
-		Id: strconv.Itoa(int(*resp.Response.RecordId)),
-	}
+	data := *record
+	data.Id = strconv.Itoa(int(*resp.Response.RecordId))
 
-	return data, nil
+	return &data, nil
 
 }
 
 func (p *TecentDnspodDriver) UpdateRecord(zone *dns.Zone, record *dns.Record) (*dns.Record, error) {
 
+	return p.modifyRecord(zone, record, dnspodWeight(record))
+
+}
+
+// 修改完整记录，权重为空时不修改
+
+func (p *TecentDnspodDriver) modifyRecord(zone *dns.Zone, record *dns.Record, weight *uint64) (*dns.Record, error) {
+
 	id, _ := strconv.Atoi(record.Id)
 	recordId := uint64(id)
 
 	ttl := uint64(record.TTL)
 	priority := uint64(record.Priority)
+	recordType := dnspodRecordType(record.Type)
 
-	_, err := p.dnspod.ModifyRecord(&dnspod.ModifyRecordRequest{
+	recordLine, err := p.recordLine(zone, record.Line)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = p.dnspod.ModifyRecord(&dnspod.ModifyRecordRequest{
 		Domain:     &zone.Domain,
 		RecordId:   &recordId,
 		SubDomain:  &record.Name,
-		RecordType: (*string)(&record.Type),
-		RecordLine: &record.Line,
+		RecordType: &recordType,
+		RecordLine: &recordLine,
 		Value:      &record.Value,
 		TTL:        &ttl,
 		MX:         &priority,
+		Weight:     weight,
+		Status:     dnspodStatus(record.Status),
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	return record, nil
+	data := *record
+
+	return &data, nil
 
 }
 
@@ -258,3 +336,203 @@ func (p *TecentDnspodDriver) DeleteRecord(zone *dns.Zone, record *dns.Record) er
 	return err
 
 }
+
+func (p *TecentDnspodDriver) ListRecordTypes() ([]dns.RecordType, error) {
+
+	types := []dns.RecordType{
+		dns.RecordTypeA,
+		dns.RecordTypeAAAA,
+		dns.RecordTypeCNAME,
+		dns.RecordTypeMX,
+		dns.RecordTypeTXT,
+		dns.RecordTypeNS,
+		dns.RecordTypeSRV,
+		dns.RecordTypeCAA,
+		dns.RecordTypeSPF,
+		dns.RecordTypeHTTPS,
+		dns.RecordTypeSVCB,
+		dns.RecordTypeREDIRECT_URL,
+		dns.RecordTypeFORWARD_URL,
+	}
+
+	return types, nil
+
+}
+
+func (p *TecentDnspodDriver) ListRecordLines(zone *dns.Zone) ([]*dns.Line, error) {
+
+	info, err := p.dnspod.DescribeDomain(&dnspod.DescribeDomainRequest{
+		Domain: &zone.Domain,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.dnspod.DescribeRecordLineList(&dnspod.DescribeRecordLineListRequest{
+		Domain:      &zone.Domain,
+		DomainGrade: info.Response.DomainInfo.Grade,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]*dns.Line, 0)
+
+	for _, line := range resp.Response.LineList {
+		lines = append(lines, &dns.Line{
+			Id:   *line.LineId,
+			Code: dns.ParseRecordLine(*line.Name),
+			Name: *line.Name,
+		})
+	}
+
+	return lines, nil
+
+}
+
+// 转换为 DNSPod 线路名称，并检查套餐是否支持
+
+func (p *TecentDnspodDriver) recordLine(zone *dns.Zone, line dns.RecordLine) (string, error) {
+
+	if line.IsDefault() {
+		return line.Label(), nil
+	}
+
+	lines, err := p.ListRecordLines(zone)
+	if err != nil {
+		return "", err
+	}
+
+	for _, item := range lines {
+		if item.Name == line.Label() {
+			return item.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", dns.LineNotSupportedError, line)
+
+}
+
+func (p *TecentDnspodDriver) SetRecordStatus(zone *dns.Zone, record *dns.Record, status dns.RecordStatus) error {
+
+	id, _ := strconv.Atoi(record.Id)
+	recordId := uint64(id)
+
+	_, err := p.dnspod.ModifyRecordStatus(&dnspod.ModifyRecordStatusRequest{
+		Domain:   &zone.Domain,
+		RecordId: &recordId,
+		Status:   dnspodStatus(status),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	record.Status = status
+	return nil
+
+}
+
+func (p *TecentDnspodDriver) SetRecordWeight(zone *dns.Zone, record *dns.Record, weight int) error {
+
+	// 权重需随完整记录一起修改，显式传递以支持权重 0
+	data, err := p.DetailRecord(zone, record)
+	if err != nil {
+		return err
+	}
+
+	data.Weight = weight
+	if _, err = p.modifyRecord(zone, data, tc.Uint64Ptr(uint64(weight))); err != nil {
+		return err
+	}
+
+	record.Weight = weight
+	return nil
+
+}
+
+// 转换为 DNSPod 记录状态
+
+func dnspodStatus(status dns.RecordStatus) *string {
+
+	switch status {
+	case dns.RecordStatusENABLED:
+		return tc.StringPtr("ENABLE")
+	case dns.RecordStatusDISABLED:
+		return tc.StringPtr("DISABLE")
+	}
+
+	return nil
+
+}
+
+// 转换为 DNSPod 记录权重，0 表示不设置，设置权重 0 需使用 SetRecordWeight
+
+func dnspodWeight(record *dns.Record) *uint64 {
+
+	if record.Weight <= 0 {
+		return nil
+	}
+
+	return tc.Uint64Ptr(uint64(record.Weight))
+
+}
+
+// 读取可能为空的数值
+
+func dnspodUint64(v *uint64) uint64 {
+
+	if v == nil {
+		return 0
+	}
+
+	return *v
+
+}
+
+// 转换 DNSPod 记录状态
+
+func dnspodRecordStatus(status string) dns.RecordStatus {
+
+	if status == "DISABLE" {
+		return dns.RecordStatusDISABLED
+	}
+
+	return dns.RecordStatusENABLED
+
+}
+
+// 记录类型名称映射
+
+var dnspodRecordTypes = map[dns.RecordType]string{
+	dns.RecordTypeREDIRECT_URL: "显性URL",
+	dns.RecordTypeFORWARD_URL:  "隐性URL",
+}
+
+// 转换为 DNSPod 记录类型
+
+func dnspodRecordType(t dns.RecordType) string {
+
+	if name, ok := dnspodRecordTypes[t]; ok {
+		return name
+	}
+
+	return string(t)
+
+}
+
+// 转换 DNSPod 记录类型
+
+func dnspodRecordTypeOf(name string) dns.RecordType {
+
+	for t, v := range dnspodRecordTypes {
+		if v == name {
+			return t
+		}
+	}
+
+	return dns.RecordType(name)
+
+}
@@ -0,0 +1,52 @@
+package dns
+
+import (
+	"strings"
+)
+
+// Check record matches filter, empty status of record means enabled
+func (f *RecordFilter) Match(zone *Zone, record *Record) bool {
+
+	if f == nil {
+		return true
+	}
+
+	name := zone.RelativeName(record.Name)
+
+	if f.Name != "" && !strings.EqualFold(name, zone.RelativeName(f.Name)) {
+		return false
+	}
+
+	if f.Keyword != "" && !strings.Contains(strings.ToLower(name), strings.ToLower(f.Keyword)) {
+		return false
+	}
+
+	if f.Type != "" && f.Type != record.Type {
+		return false
+	}
+
+	if f.Value != "" && strings.TrimSuffix(f.Value, ".") != strings.TrimSuffix(record.Value, ".") {
+		return false
+	}
+
+	if f.Line != "" && f.Line.IsDefault() != record.Line.IsDefault() {
+		return false
+	}
+
+	if f.Line != "" && !f.Line.IsDefault() && f.Line != record.Line {
+		return false
+	}
+
+	if f.Status != "" {
+		status := record.Status
+		if status == "" {
+			status = RecordStatusENABLED
+		}
+		if status != f.Status {
+			return false
+		}
+	}
+
+	return true
+
+}
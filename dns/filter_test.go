@@ -0,0 +1,96 @@
+package dns
+
+import (
+	"testing"
+)
+
+func TestRecordFilterMatch(t *testing.T) {
+
+	zone := &Zone{Domain: "example.com"}
+	record := &Record{Name: "www.example.com.", Type: RecordTypeCNAME, Value: "cdn.example.net.", Line: RecordLineTELECOM}
+
+	tests := []struct {
+		name   string
+		filter *RecordFilter
+		want   bool
+	}{
+		{"nil filter", nil, true},
+		{"empty filter", &RecordFilter{}, true},
+		{"relative name", &RecordFilter{Name: "WWW"}, true},
+		{"fully qualified name", &RecordFilter{Name: "www.example.com."}, true},
+		{"other name", &RecordFilter{Name: "ww"}, false},
+		{"keyword", &RecordFilter{Keyword: "W"}, true},
+		{"keyword outside relative name", &RecordFilter{Keyword: "example"}, false},
+		{"type", &RecordFilter{Type: RecordTypeCNAME}, true},
+		{"other type", &RecordFilter{Type: RecordTypeA}, false},
+		{"value without dot", &RecordFilter{Value: "cdn.example.net"}, true},
+		{"other value", &RecordFilter{Value: "cdn.example.org"}, false},
+		{"line", &RecordFilter{Line: RecordLineTELECOM}, true},
+		{"other line", &RecordFilter{Line: RecordLineUNICOM}, false},
+		{"default line", &RecordFilter{Line: RecordLineDEFAULT}, false},
+		{"all conditions", &RecordFilter{Name: "www", Type: RecordTypeCNAME, Line: RecordLineTELECOM}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(zone, record); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestRecordFilterMatchDefaultLine(t *testing.T) {
+
+	zone := &Zone{Domain: "example.com"}
+
+	tests := []struct {
+		name   string
+		line   RecordLine
+		filter RecordLine
+		want   bool
+	}{
+		{"empty matches default", "", RecordLineDEFAULT, true},
+		{"default matches default", RecordLineDEFAULT, RecordLineDEFAULT, true},
+		{"empty matches explicit line", "", RecordLineTELECOM, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := &Record{Name: "@", Type: RecordTypeA, Value: "192.0.2.1", Line: tt.line}
+			if got := (&RecordFilter{Line: tt.filter}).Match(zone, record); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestRecordFilterMatchStatus(t *testing.T) {
+
+	zone := &Zone{Domain: "example.com"}
+
+	tests := []struct {
+		name   string
+		status RecordStatus
+		filter RecordStatus
+		want   bool
+	}{
+		{"empty status is enabled", "", RecordStatusENABLED, true},
+		{"empty status is not disabled", "", RecordStatusDISABLED, false},
+		{"disabled", RecordStatusDISABLED, RecordStatusDISABLED, true},
+		{"disabled is not enabled", RecordStatusDISABLED, RecordStatusENABLED, false},
+		{"any status", RecordStatusDISABLED, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := &Record{Name: "@", Type: RecordTypeA, Value: "192.0.2.1", Status: tt.status}
+			if got := (&RecordFilter{Status: tt.filter}).Match(zone, record); got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
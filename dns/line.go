@@ -0,0 +1,96 @@
+package dns
+
+// 线路中文名称
+
+var recordLineLabels = map[RecordLine]string{
+	RecordLineDEFAULT:   "默认",
+	RecordLineTELECOM:   "电信",
+	RecordLineUNICOM:    "联通",
+	RecordLineMOBILE:    "移动",
+	RecordLineEDUCATION: "教育网",
+	RecordLineOVERSEAS:  "境外",
+	RecordLineSEARCH:    "搜索引擎",
+
+	RecordLineBEIJING:      "北京",
+	RecordLineTIANJIN:      "天津",
+	RecordLineHEBEI:        "河北",
+	RecordLineSHANXI:       "山西",
+	RecordLineNEIMENGGU:    "内蒙古",
+	RecordLineLIAONING:     "辽宁",
+	RecordLineJILIN:        "吉林",
+	RecordLineHEILONGJIANG: "黑龙江",
+	RecordLineSHANGHAI:     "上海",
+	RecordLineJIANGSU:      "江苏",
+	RecordLineZHEJIANG:     "浙江",
+	RecordLineANHUI:        "安徽",
+	RecordLineFUJIAN:       "福建",
+	RecordLineJIANGXI:      "江西",
+	RecordLineSHANDONG:     "山东",
+	RecordLineHENAN:        "河南",
+	RecordLineHUBEI:        "湖北",
+	RecordLineHUNAN:        "湖南",
+	RecordLineGUANGDONG:    "广东",
+	RecordLineGUANGXI:      "广西",
+	RecordLineHAINAN:       "海南",
+	RecordLineCHONGQING:    "重庆",
+	RecordLineSICHUAN:      "四川",
+	RecordLineGUIZHOU:      "贵州",
+	RecordLineYUNNAN:       "云南",
+	RecordLineXIZANG:       "西藏",
+	RecordLineSHAANXI:      "陕西",
+	RecordLineGANSU:        "甘肃",
+	RecordLineQINGHAI:      "青海",
+	RecordLineNINGXIA:      "宁夏",
+	RecordLineXINJIANG:     "新疆",
+	RecordLineHONGKONG:     "中国香港",
+	RecordLineMACAO:        "中国澳门",
+	RecordLineTAIWAN:       "中国台湾",
+
+	RecordLineUS: "美国",
+	RecordLineCA: "加拿大",
+	RecordLineGB: "英国",
+	RecordLineDE: "德国",
+	RecordLineFR: "法国",
+	RecordLineRU: "俄罗斯",
+	RecordLineJP: "日本",
+	RecordLineKR: "韩国",
+	RecordLineSG: "新加坡",
+	RecordLineIN: "印度",
+	RecordLineAU: "澳大利亚",
+	RecordLineBR: "巴西",
+}
+
+// Check line is the default one
+func (l RecordLine) IsDefault() bool {
+
+	return l == "" || l == RecordLineDEFAULT
+
+}
+
+// Get chinese display name of line, provider specific lines are returned as is
+func (l RecordLine) Label() string {
+
+	if l == "" {
+		return recordLineLabels[RecordLineDEFAULT]
+	}
+
+	if label, ok := recordLineLabels[l]; ok {
+		return label
+	}
+
+	return string(l)
+
+}
+
+// Parse chinese display name into line, unknown names are kept as provider specific lines
+func ParseRecordLine(label string) RecordLine {
+
+	for line, v := range recordLineLabels {
+		if v == label {
+			return line
+		}
+	}
+
+	return RecordLine(label)
+
+}
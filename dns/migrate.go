@@ -0,0 +1,270 @@
+package dns
+
+import (
+	"fmt"
+	"strings"
+)
+
+const defaultTTL = 600
+
+// Copy zone and its records from src to dst provider, zone is created on dst if missing
+func MigrateZone(src, dst DnsProvider, zone *Zone, opts *MigrateOpts) (*MigrateReport, error) {
+
+	if opts == nil {
+		opts = &MigrateOpts{}
+	}
+
+	records, err := src.ListRecords(zone, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := migrateTarget(dst, zone, opts.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MigrateReport{Zone: target, Nameservers: target.DnsServers}
+
+	types, err := dst.ListRecordTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	supported := map[RecordType]bool{}
+	for _, t := range types {
+		supported[t] = true
+	}
+
+	existing := []*Record{}
+	if target.Id != "" {
+		if existing, err = dst.ListRecords(target, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	lines := map[RecordLine]bool{}
+	for _, record := range records {
+		if !record.Line.IsDefault() && target.Id != "" {
+			items, err := dst.ListRecordLines(target)
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				lines[item.Code] = true
+			}
+			break
+		}
+	}
+
+	minTTL := opts.MinTTL
+	if target.MinTTL > minTTL {
+		minTTL = target.MinTTL
+	}
+
+	for _, record := range records {
+		data := migrateRecord(zone, record, minTTL)
+
+		if reason := migrateSkipReason(data, supported, lines); reason != "" {
+			report.Skipped = append(report.Skipped, &MigrateSkip{record, reason})
+			continue
+		}
+
+		if found := migrateFind(target, existing, data); found != nil {
+			report.Existing = append(report.Existing, found)
+			continue
+		}
+
+		if opts.DryRun {
+			report.Created = append(report.Created, data)
+			continue
+		}
+
+		created, err := dst.CreateRecord(target, data)
+		if err != nil {
+			report.Failed = append(report.Failed, &MigrateSkip{record, err.Error()})
+			continue
+		}
+
+		if reason := migrateExtras(dst, target, created, data); reason != "" {
+			report.Partial = append(report.Partial, &MigrateSkip{record, reason})
+		}
+
+		report.Created = append(report.Created, created)
+	}
+
+	return report, nil
+
+}
+
+// Summary of migration with nameservers to set at the registrar
+func (r *MigrateReport) String() string {
+
+	b := &strings.Builder{}
+
+	fmt.Fprintf(b, "zone %s: %d created, %d existing, %d skipped, %d failed, %d partial\n",
+		r.Zone.Domain, len(r.Created), len(r.Existing), len(r.Skipped), len(r.Failed), len(r.Partial),
+	)
+
+	for _, item := range r.Skipped {
+		fmt.Fprintf(b, "skipped %s %s %s: %s\n", item.Record.Name, item.Record.Type, item.Record.Value, item.Reason)
+	}
+	for _, item := range r.Failed {
+		fmt.Fprintf(b, "failed %s %s %s: %s\n", item.Record.Name, item.Record.Type, item.Record.Value, item.Reason)
+	}
+	for _, item := range r.Partial {
+		fmt.Fprintf(b, "partial %s %s %s: %s\n", item.Record.Name, item.Record.Type, item.Record.Value, item.Reason)
+	}
+
+	if len(r.Nameservers) > 0 {
+		fmt.Fprintf(b, "nameservers: %s\n", strings.Join(r.Nameservers, " "))
+	}
+
+	return b.String()
+
+}
+
+// 查找或创建目标区域
+
+func migrateTarget(dst DnsProvider, zone *Zone, dryRun bool) (*Zone, error) {
+
+	zones, err := dst.ListZones()
+	if err != nil {
+		return nil, err
+	}
+
+	domain := strings.TrimSuffix(zone.Domain, ".")
+	for _, item := range zones {
+		if strings.EqualFold(item.Domain, domain) {
+			return dst.DetailZone(item)
+		}
+	}
+
+	if dryRun {
+		return &Zone{Domain: domain}, nil
+	}
+
+	created, err := dst.CreateZone(&Zone{Domain: domain, Description: zone.Description})
+	if err != nil {
+		return nil, err
+	}
+
+	if detail, err := dst.DetailZone(created); err == nil {
+		return detail, nil
+	}
+
+	return created, nil
+
+}
+
+// 转换为目标记录，名称使用相对名称
+
+func migrateRecord(zone *Zone, record *Record, minTTL int) *Record {
+
+	data := &Record{
+		Name:        zone.RelativeName(record.Name),
+		Type:        record.Type,
+		Value:       record.Value,
+		Line:        record.Line,
+		TTL:         record.TTL,
+		Priority:    record.Priority,
+		Weight:      record.Weight,
+		Status:      record.Status,
+		Description: record.Description,
+	}
+
+	if data.Line.IsDefault() {
+		data.Line = ""
+	}
+
+	// Cloudflare 使用 1 表示自动
+	if data.TTL <= 1 {
+		data.TTL = defaultTTL
+	}
+	if data.TTL < minTTL {
+		data.TTL = minTTL
+	}
+
+	switch data.Type {
+	case RecordTypeCNAME, RecordTypeMX, RecordTypeNS, RecordTypePTR:
+		data.Value = strings.TrimSuffix(data.Value, ".")
+	case RecordTypeSRV:
+		fields := strings.Fields(data.Value)
+		if len(fields) == 3 {
+			fields = append([]string{fmt.Sprint(data.Priority)}, fields...)
+		}
+		if len(fields) == 4 {
+			fields[3] = strings.TrimSuffix(fields[3], ".")
+		}
+		data.Value = strings.Join(fields, " ")
+	}
+
+	return data
+
+}
+
+// 补充设置创建时未生效的状态和权重，返回无法保留的原因
+
+func migrateExtras(dst DnsProvider, zone *Zone, created, record *Record) string {
+
+	reasons := []string{}
+
+	if record.Status == RecordStatusDISABLED && created.Status != RecordStatusDISABLED {
+		if err := dst.SetRecordStatus(zone, created, RecordStatusDISABLED); err != nil {
+			reasons = append(reasons, "disabled status not kept: "+err.Error())
+		}
+	}
+
+	if record.Weight > 0 && created.Weight != record.Weight {
+		if err := dst.SetRecordWeight(zone, created, record.Weight); err != nil {
+			reasons = append(reasons, fmt.Sprintf("weight %d not kept: %s", record.Weight, err))
+		}
+	}
+
+	return strings.Join(reasons, "; ")
+
+}
+
+// 检查记录能否迁移
+
+func migrateSkipReason(record *Record, types map[RecordType]bool, lines map[RecordLine]bool) string {
+
+	if record.Type == RecordTypeSOA {
+		return "SOA is managed by destination"
+	}
+
+	if record.Type == RecordTypeNS && record.Name == "@" {
+		return "apex NS is replaced by destination nameservers"
+	}
+
+	if !types[record.Type] {
+		return fmt.Sprintf("record type %s not supported by destination", record.Type)
+	}
+
+	if !record.Line.IsDefault() && !lines[record.Line] {
+		return fmt.Sprintf("line %s not supported by destination", record.Line)
+	}
+
+	return ""
+
+}
+
+// 查找目标已存在的相同记录
+
+func migrateFind(zone *Zone, records []*Record, record *Record) *Record {
+
+	for _, item := range records {
+		if !strings.EqualFold(zone.RelativeName(item.Name), record.Name) || item.Type != record.Type {
+			continue
+		}
+		if item.Line.IsDefault() != record.Line.IsDefault() || (!item.Line.IsDefault() && item.Line != record.Line) {
+			continue
+		}
+		if strings.TrimSuffix(item.Value, ".") == record.Value {
+			return item
+		}
+	}
+
+	return nil
+
+}
@@ -0,0 +1,335 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// 内存平台，仅实现迁移所需的方法
+type memProvider struct {
+	DnsProvider
+	zones   []*Zone
+	records map[string][]*Record
+	types   []RecordType
+	lines   []RecordLine
+	failed  string // 创建该值的记录时失败
+	zoneErr error  // 创建区域时返回的错误
+	nextId  int
+}
+
+func newMemProvider(types []RecordType, lines ...RecordLine) *memProvider {
+
+	return &memProvider{records: map[string][]*Record{}, types: types, lines: lines}
+
+}
+
+func (p *memProvider) ListZones() ([]*Zone, error) {
+
+	return p.zones, nil
+
+}
+
+func (p *memProvider) DetailZone(zone *Zone) (*Zone, error) {
+
+	for _, item := range p.zones {
+		if item.Id == zone.Id {
+			detail := *item
+			return &detail, nil
+		}
+	}
+
+	return nil, ZoneDoesNotExistError
+
+}
+
+func (p *memProvider) CreateZone(zone *Zone) (*Zone, error) {
+
+	if p.zoneErr != nil {
+		return nil, p.zoneErr
+	}
+
+	p.nextId++
+
+	item := *zone
+	item.Id = fmt.Sprint("zone-", p.nextId)
+	item.DnsServers = []string{"ns1.dst.test", "ns2.dst.test"}
+	p.zones = append(p.zones, &item)
+
+	created := item
+	return &created, nil
+
+}
+
+func (p *memProvider) ListRecords(zone *Zone, filter *RecordFilter) ([]*Record, error) {
+
+	records := []*Record{}
+	for _, record := range p.records[zone.Id] {
+		if filter.Match(zone, record) {
+			records = append(records, record)
+		}
+	}
+
+	return records, nil
+
+}
+
+func (p *memProvider) CreateRecord(zone *Zone, record *Record) (*Record, error) {
+
+	if record.Value == p.failed {
+		return nil, errors.New("quota exceeded")
+	}
+
+	p.nextId++
+
+	// 创建时不支持权重
+	item := *record
+	item.Id = fmt.Sprint(p.nextId)
+	item.Weight = 0
+	p.records[zone.Id] = append(p.records[zone.Id], &item)
+
+	created := item
+	return &created, nil
+
+}
+
+func (p *memProvider) SetRecordStatus(zone *Zone, record *Record, status RecordStatus) error {
+
+	for _, item := range p.records[zone.Id] {
+		if item.Id == record.Id {
+			item.Status = status
+			return nil
+		}
+	}
+
+	return RecordDoesNotExistError
+
+}
+
+func (p *memProvider) SetRecordWeight(zone *Zone, record *Record, weight int) error {
+
+	return NotSupportedError
+
+}
+
+func (p *memProvider) ListRecordTypes() ([]RecordType, error) {
+
+	return p.types, nil
+
+}
+
+func (p *memProvider) ListRecordLines(zone *Zone) ([]*Line, error) {
+
+	lines := []*Line{}
+	for _, code := range p.lines {
+		lines = append(lines, &Line{Code: code})
+	}
+
+	return lines, nil
+
+}
+
+// 记录摘要，格式为 name type line ttl priority value
+func migrateKeys(records []*Record) []string {
+
+	keys := []string{}
+	for _, record := range records {
+		keys = append(keys, fmt.Sprintf("%s %s %s %d %d %s", record.Name, record.Type, record.Line, record.TTL, record.Priority, record.Value))
+	}
+	sort.Strings(keys)
+
+	return keys
+
+}
+
+func TestMigrateZone(t *testing.T) {
+
+	zone := &Zone{Id: "src", Domain: "example.com"}
+
+	src := newMemProvider(nil)
+	src.records["src"] = []*Record{
+		{Name: "example.com.", Type: RecordTypeA, Value: "192.0.2.1", TTL: 600},
+		{Name: "www.example.com", Type: RecordTypeCNAME, Value: "cdn.example.net.", TTL: 1},
+		{Name: "@", Type: RecordTypeMX, Value: "mx.example.com.", Priority: 10, TTL: 60},
+		{Name: "_sip._tcp", Type: RecordTypeSRV, Value: "5 5060 sip.example.com.", Priority: 20, TTL: 600},
+		{Name: "@", Type: RecordTypeSOA, Value: "ns1.src.test. admin.example.com. 1 3600 600 86400 600", TTL: 600},
+		{Name: "@", Type: RecordTypeNS, Value: "ns1.src.test.", TTL: 86400},
+		{Name: "@", Type: RecordTypeCAA, Value: `0 issue "letsencrypt.org"`, TTL: 600},
+		{Name: "cn", Type: RecordTypeA, Value: "192.0.2.5", Line: RecordLineTELECOM, TTL: 600},
+		{Name: "cn", Type: RecordTypeA, Value: "192.0.2.6", Line: RecordLineUNICOM, TTL: 600},
+		{Name: "api", Type: RecordTypeA, Value: "192.0.2.7", TTL: 600},
+	}
+
+	dst := newMemProvider([]RecordType{RecordTypeA, RecordTypeCNAME, RecordTypeMX, RecordTypeSRV, RecordTypeNS}, RecordLineTELECOM)
+	dst.zones = []*Zone{{Id: "dst", Domain: "Example.com", MinTTL: 120, DnsServers: []string{"ns1.dst.test"}}}
+	dst.records["dst"] = []*Record{{Id: "1", Name: "@", Type: RecordTypeA, Value: "192.0.2.1", TTL: 600}}
+	dst.failed = "192.0.2.7"
+
+	report, err := MigrateZone(src, dst, zone, &MigrateOpts{MinTTL: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := []string{
+		"_sip._tcp SRV  600 20 20 5 5060 sip.example.com",
+		"cn A telecom 600 0 192.0.2.5",
+		"www CNAME  600 0 cdn.example.net",
+		"@ MX  300 10 mx.example.com",
+	}
+	sort.Strings(created)
+
+	if got := migrateKeys(report.Created); !reflect.DeepEqual(got, created) {
+		t.Errorf("created:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(created, "\n"))
+	}
+
+	if len(report.Existing) != 1 || report.Existing[0].Id != "1" {
+		t.Errorf("existing = %v", report.Existing)
+	}
+
+	skipped := []string{}
+	for _, item := range report.Skipped {
+		skipped = append(skipped, string(item.Record.Type)+" "+item.Reason)
+	}
+	want := []string{
+		"SOA SOA is managed by destination",
+		"NS apex NS is replaced by destination nameservers",
+		"CAA record type CAA not supported by destination",
+		"A line unicom not supported by destination",
+	}
+	if !reflect.DeepEqual(skipped, want) {
+		t.Errorf("skipped = %q, want %q", skipped, want)
+	}
+
+	if len(report.Failed) != 1 || report.Failed[0].Record.Value != "192.0.2.7" {
+		t.Errorf("failed = %v", report.Failed)
+	}
+
+	if len(dst.records["dst"]) != 5 {
+		t.Errorf("destination has %d records, want 5", len(dst.records["dst"]))
+	}
+
+	out := report.String()
+	for _, line := range []string{
+		"zone Example.com: 4 created, 1 existing, 4 skipped, 1 failed, 0 partial\n",
+		"failed api A 192.0.2.7: quota exceeded\n",
+		"nameservers: ns1.dst.test\n",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("String() = %q, missing %q", out, line)
+		}
+	}
+
+}
+
+func TestMigrateZoneCreatesZone(t *testing.T) {
+
+	zone := &Zone{Id: "src", Domain: "example.org.", Description: "main"}
+
+	tests := []struct {
+		name    string
+		dryRun  bool
+		zones   int
+		records int
+	}{
+		{"dry run leaves destination untouched", true, 0, 0},
+		{"zone created on destination", false, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newMemProvider(nil)
+			src.records["src"] = []*Record{{Name: "www", Type: RecordTypeA, Value: "192.0.2.1", TTL: 600}}
+
+			dst := newMemProvider([]RecordType{RecordTypeA})
+
+			report, err := MigrateZone(src, dst, zone, &MigrateOpts{DryRun: tt.dryRun})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(report.Created) != 1 {
+				t.Errorf("created = %v, want 1 record", report.Created)
+			}
+
+			if len(dst.zones) != tt.zones {
+				t.Fatalf("destination has %d zones, want %d", len(dst.zones), tt.zones)
+			}
+
+			if tt.zones > 0 {
+				if dst.zones[0].Domain != "example.org" || dst.zones[0].Description != "main" {
+					t.Errorf("created zone = %+v", dst.zones[0])
+				}
+				if len(dst.records[dst.zones[0].Id]) != tt.records || len(report.Nameservers) != 2 {
+					t.Errorf("records %d, nameservers %v", len(dst.records[dst.zones[0].Id]), report.Nameservers)
+				}
+			}
+		})
+	}
+
+}
+
+func TestMigrateZoneExtras(t *testing.T) {
+
+	zone := &Zone{Id: "src", Domain: "example.com"}
+
+	src := newMemProvider(nil)
+	src.records["src"] = []*Record{
+		{Name: "old", Type: RecordTypeA, Value: "192.0.2.1", Status: RecordStatusDISABLED, TTL: 600},
+		{Name: "lb", Type: RecordTypeA, Value: "192.0.2.2", Weight: 5, TTL: 600},
+		{Name: "www", Type: RecordTypeA, Value: "192.0.2.3", Status: RecordStatusENABLED, TTL: 600},
+	}
+
+	dst := newMemProvider([]RecordType{RecordTypeA})
+	dst.zones = []*Zone{{Id: "dst", Domain: "example.com"}}
+
+	report, err := MigrateZone(src, dst, zone, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Created) != 3 {
+		t.Fatalf("created = %v, want 3 records", report.Created)
+	}
+
+	status := map[string]RecordStatus{}
+	for _, record := range dst.records["dst"] {
+		status[record.Name] = record.Status
+	}
+	if status["old"] != RecordStatusDISABLED {
+		t.Errorf("status of old = %q, want disabled", status["old"])
+	}
+
+	if len(report.Partial) != 1 || report.Partial[0].Record.Name != "lb" {
+		t.Fatalf("partial = %v, want lb", report.Partial)
+	}
+	if !strings.HasPrefix(report.Partial[0].Reason, "weight 5 not kept") {
+		t.Errorf("reason = %q", report.Partial[0].Reason)
+	}
+
+	if !strings.Contains(report.String(), "partial lb A 192.0.2.2: weight 5 not kept") {
+		t.Errorf("String() = %q", report.String())
+	}
+
+}
+
+func TestMigrateZoneCreateZoneError(t *testing.T) {
+
+	src := newMemProvider(nil)
+	src.records["src"] = []*Record{{Name: "www", Type: RecordTypeA, Value: "192.0.2.1", TTL: 600}}
+
+	dst := newMemProvider([]RecordType{RecordTypeA})
+	dst.zoneErr = fmt.Errorf("%w: account id required", NotSupportedError)
+
+	_, err := MigrateZone(src, dst, &Zone{Id: "src", Domain: "example.com"}, nil)
+	if !errors.Is(err, NotSupportedError) {
+		t.Fatalf("err = %v, want NotSupportedError", err)
+	}
+
+	if len(dst.records) != 0 {
+		t.Errorf("records = %v, want none", dst.records)
+	}
+
+}
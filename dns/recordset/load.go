@@ -0,0 +1,32 @@
+package recordset
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Parse spec from YAML or JSON document
+func Load(data []byte) (*Spec, error) {
+
+	spec := &Spec{}
+
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, err
+	}
+
+	return spec, nil
+
+}
+
+// Parse spec from YAML or JSON file
+func LoadFile(path string) (*Spec, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(data)
+
+}
@@ -0,0 +1,222 @@
+package recordset
+
+import (
+	"strings"
+
+	"github.com/rehiy/cloudgo/dns"
+)
+
+// 执行顺序：删除记录、更新记录、创建记录、创建标记、删除标记
+
+const (
+	phaseDelete = iota
+	phaseUpdate
+	phaseCreate
+	phaseMarkerCreate
+	phaseMarkerDelete
+	phaseCount
+)
+
+type planner struct {
+	spec     *Spec
+	zone     *dns.Zone
+	existing map[string][]*dns.Record
+	groups   map[string]bool
+	markers  map[string]*dns.Record
+	marked   map[string]bool
+	phases   [phaseCount][]*Action
+	plan     *Plan
+}
+
+// 规划单个记录集
+
+func (p *planner) planSet(key, name string, set *RecordSet) {
+
+	owner := p.spec.Owner
+	current := p.existing[key]
+
+	if owner != nil && len(owner.Names) > 0 && !matchNames(owner.Names, name) {
+		p.skip(key, "name outside managed names")
+		return
+	}
+
+	// 同名同类型的记录在任一线路存在时，需已归属本方
+	if owner != nil && owner.Marker != "" {
+		marker := p.markers[markerName(name, set.Type)]
+		if (p.groups[groupKey(name, set.Type)] || marker != nil) && !p.owned(name, set.Type) {
+			p.skip(key, "record set not owned")
+			return
+		}
+	}
+
+	group := groupKey(name, set.Type)
+
+	ttl := set.TTL
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+
+	used := make([]bool, len(current))
+	pending := []string{}
+
+	// 值相同的记录保留，必要时更新 TTL 和优先级
+	for _, value := range set.Values {
+		index := -1
+		for j, record := range current {
+			if !used[j] && normalizeValue(set.Type, record.Value) == normalizeValue(set.Type, value) {
+				index = j
+				break
+			}
+		}
+		if index < 0 {
+			pending = append(pending, value)
+			continue
+		}
+		used[index] = true
+		record := current[index]
+		if record.TTL != ttl || (set.Type == dns.RecordTypeMX && record.Priority != set.Priority) {
+			p.add(ActionUpdate, key, group, p.desired(record, name, set, value, ttl), record, false)
+		}
+	}
+
+	// 新值优先复用多余的记录
+	for _, value := range pending {
+		index := -1
+		for j := range current {
+			if !used[j] {
+				index = j
+				break
+			}
+		}
+		if index < 0 {
+			p.add(ActionCreate, key, group, p.desired(nil, name, set, value, ttl), nil, false)
+			continue
+		}
+		used[index] = true
+		p.add(ActionUpdate, key, group, p.desired(current[index], name, set, value, ttl), current[index], false)
+	}
+
+	for j, record := range current {
+		if !used[j] {
+			p.add(ActionDelete, key, group, nil, record, false)
+		}
+	}
+
+	if owner != nil && owner.Marker != "" && !p.marked[group] {
+		p.marked[group] = true
+		if p.markers[markerName(name, set.Type)] == nil {
+			marker := &dns.Record{
+				Name:  markerName(name, set.Type),
+				Type:  dns.RecordTypeTXT,
+				Value: "owner=" + owner.Marker,
+				TTL:   defaultTTL,
+			}
+			p.add(ActionCreate, group, group, marker, nil, true)
+		}
+	}
+
+}
+
+// 生成期望记录，base 为空时新建
+
+func (p *planner) desired(base *dns.Record, name string, set *RecordSet, value string, ttl int) *dns.Record {
+
+	record := &dns.Record{Name: name, Type: set.Type, Line: set.Line}
+	if base != nil {
+		*record = *base
+	}
+
+	record.Value = value
+	record.TTL = ttl
+	record.Priority = set.Priority
+
+	return record
+
+}
+
+// 检查记录是否为归属标记
+
+func (p *planner) isMarker(name string, record *dns.Record) bool {
+
+	if p.spec.Owner == nil || p.spec.Owner.Marker == "" {
+		return false
+	}
+
+	return record.Type == dns.RecordTypeTXT && strings.HasPrefix(strings.ToLower(name), "_owner-")
+
+}
+
+// 检查归属标记是否属于本方
+
+func (p *planner) ownMarker(marker *dns.Record) bool {
+
+	return normalizeValue(dns.RecordTypeTXT, marker.Value) == "owner="+p.spec.Owner.Marker
+
+}
+
+// 检查记录集是否归属于本方
+
+func (p *planner) owned(name string, recordType dns.RecordType) bool {
+
+	owner := p.spec.Owner
+	if owner == nil {
+		return false
+	}
+
+	if len(owner.Names) > 0 && !matchNames(owner.Names, name) {
+		return false
+	}
+
+	if owner.Marker != "" {
+		marker := p.markers[markerName(name, recordType)]
+		if marker == nil || !p.ownMarker(marker) {
+			return false
+		}
+	}
+
+	return true
+
+}
+
+// 添加动作
+
+func (p *planner) add(actionType ActionType, key, group string, record, current *dns.Record, marker bool) {
+
+	phase := phaseDelete
+
+	switch {
+	case marker && actionType == ActionCreate:
+		phase = phaseMarkerCreate
+	case marker:
+		phase = phaseMarkerDelete
+	case actionType == ActionUpdate:
+		phase = phaseUpdate
+	case actionType == ActionCreate:
+		phase = phaseCreate
+	}
+
+	action := &Action{actionType, key, record, current, marker, group}
+	p.phases[phase] = append(p.phases[phase], action)
+
+}
+
+// 跳过记录集
+
+func (p *planner) skip(key, reason string) {
+
+	p.plan.Skipped = append(p.plan.Skipped, &Skipped{key, reason})
+
+}
+
+// 按执行顺序排列动作
+
+func (p *planner) order() []*Action {
+
+	actions := []*Action{}
+	for _, phase := range p.phases {
+		actions = append(actions, phase...)
+	}
+
+	return actions
+
+}
@@ -0,0 +1,207 @@
+package recordset
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/rehiy/cloudgo/dns"
+)
+
+// 模拟平台，仅实现规划所需的方法
+type fakeProvider struct {
+	dns.DnsProvider
+	records []*dns.Record
+}
+
+func (p *fakeProvider) ListZones() ([]*dns.Zone, error) {
+
+	return []*dns.Zone{{Id: "z1", Domain: "example.com"}}, nil
+
+}
+
+func (p *fakeProvider) ListRecords(zone *dns.Zone, filter *dns.RecordFilter) ([]*dns.Record, error) {
+
+	return p.records, nil
+
+}
+
+func record(name string, recordType dns.RecordType, value string) *dns.Record {
+
+	return &dns.Record{Id: name + "-" + value, Name: name, Type: recordType, Value: value, TTL: defaultTTL}
+
+}
+
+func marker(name, value string) *dns.Record {
+
+	return record(name, dns.RecordTypeTXT, value)
+
+}
+
+// 动作摘要，格式为 type name recordType value
+func planActions(plan *Plan) []string {
+
+	actions := []string{}
+	for _, action := range plan.Actions {
+		item := action.Record
+		if item == nil {
+			item = action.Current
+		}
+		actions = append(actions, fmt.Sprintf("%s %s %s %s", action.Type, item.Name, item.Type, item.Value))
+	}
+
+	return actions
+
+}
+
+func TestNewPlanOwnership(t *testing.T) {
+
+	www := &RecordSet{Name: "www", Type: dns.RecordTypeA, Values: []string{"192.0.2.1"}}
+
+	tests := []struct {
+		name     string
+		owner    *Ownership
+		existing []*dns.Record
+		records  []*RecordSet
+		want     []string
+		skipped  []Skipped
+	}{
+		{
+			name:     "nil owner keeps undeclared records",
+			existing: []*dns.Record{record("www", dns.RecordTypeA, "192.0.2.1"), record("old", dns.RecordTypeA, "192.0.2.9")},
+			records:  []*RecordSet{www},
+			want:     []string{},
+		},
+		{
+			name:     "nil owner updates declared sets",
+			existing: []*dns.Record{record("www", dns.RecordTypeA, "192.0.2.7")},
+			records:  []*RecordSet{www},
+			want:     []string{"update www A 192.0.2.1"},
+		},
+		{
+			name:  "owned marker allows update and delete",
+			owner: &Ownership{Marker: "me"},
+			existing: []*dns.Record{
+				record("www", dns.RecordTypeA, "192.0.2.7"),
+				marker("_owner-a.www", "owner=me"),
+				record("old", dns.RecordTypeA, "192.0.2.9"),
+				marker("_owner-a.old", `"owner=me"`),
+			},
+			records: []*RecordSet{www},
+			want: []string{
+				"delete old A 192.0.2.9",
+				"update www A 192.0.2.1",
+				`delete _owner-a.old TXT "owner=me"`,
+			},
+		},
+		{
+			name:     "marker of other owner",
+			owner:    &Ownership{Marker: "me"},
+			existing: []*dns.Record{record("www", dns.RecordTypeA, "192.0.2.7"), marker("_owner-a.www", "owner=you")},
+			records:  []*RecordSet{www},
+			want:     []string{},
+			skipped:  []Skipped{{"www A default", "record set not owned"}},
+		},
+		{
+			name:     "existing records without marker",
+			owner:    &Ownership{Marker: "me"},
+			existing: []*dns.Record{record("www", dns.RecordTypeA, "192.0.2.7"), record("old", dns.RecordTypeA, "192.0.2.9")},
+			records:  []*RecordSet{www},
+			want:     []string{},
+			skipped:  []Skipped{{"www A default", "record set not owned"}},
+		},
+		{
+			name:    "new set gets marker",
+			owner:   &Ownership{Marker: "me"},
+			records: []*RecordSet{{Name: "@", Type: dns.RecordTypeA, Values: []string{"192.0.2.1", "192.0.2.2"}}},
+			want: []string{
+				"create @ A 192.0.2.1",
+				"create @ A 192.0.2.2",
+				"create _owner-a TXT owner=me",
+			},
+		},
+		{
+			name:     "name outside managed names",
+			owner:    &Ownership{Names: []string{"app-*"}},
+			existing: []*dns.Record{record("www", dns.RecordTypeA, "192.0.2.7")},
+			records:  []*RecordSet{www},
+			want:     []string{},
+			skipped:  []Skipped{{"www A default", "name outside managed names"}},
+		},
+		{
+			name:  "managed names delete undeclared matches",
+			owner: &Ownership{Names: []string{"APP-*"}},
+			existing: []*dns.Record{
+				record("app-1", dns.RecordTypeA, "192.0.2.7"),
+				record("www", dns.RecordTypeA, "192.0.2.1"),
+			},
+			records: []*RecordSet{www},
+			want:    []string{"delete app-1 A 192.0.2.7"},
+			skipped: []Skipped{{"www A default", "name outside managed names"}},
+		},
+		{
+			name:  "marker and names must both match",
+			owner: &Ownership{Marker: "me", Names: []string{"app-*"}},
+			existing: []*dns.Record{
+				record("app-1", dns.RecordTypeA, "192.0.2.7"),
+				marker("_owner-a.app-1", "owner=me"),
+				record("app-2", dns.RecordTypeA, "192.0.2.8"),
+				record("db", dns.RecordTypeA, "192.0.2.9"),
+				marker("_owner-a.db", "owner=me"),
+			},
+			want: []string{
+				"delete app-1 A 192.0.2.7",
+				"delete _owner-a.app-1 TXT owner=me",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &fakeProvider{records: tt.existing}
+
+			plan, err := NewPlan(provider, &Spec{Zone: "example.com.", Owner: tt.owner, Records: tt.records})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := planActions(plan); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("actions = %q, want %q", got, tt.want)
+			}
+
+			skipped := []Skipped{}
+			for _, item := range plan.Skipped {
+				skipped = append(skipped, *item)
+			}
+			if len(skipped) != len(tt.skipped) || (len(skipped) > 0 && !reflect.DeepEqual(skipped, tt.skipped)) {
+				t.Errorf("skipped = %v, want %v", skipped, tt.skipped)
+			}
+		})
+	}
+
+}
+
+func TestNewPlanInvalidSpec(t *testing.T) {
+
+	tests := []struct {
+		name string
+		spec *Spec
+	}{
+		{"missing zone", &Spec{}},
+		{"unknown zone", &Spec{Zone: "example.net"}},
+		{"missing values", &Spec{Zone: "example.com", Records: []*RecordSet{{Name: "www", Type: dns.RecordTypeA}}}},
+		{"duplicate set", &Spec{Zone: "example.com", Records: []*RecordSet{
+			{Name: "www", Type: dns.RecordTypeA, Values: []string{"192.0.2.1"}},
+			{Name: "WWW.example.com.", Type: dns.RecordTypeA, Line: dns.RecordLineDEFAULT, Values: []string{"192.0.2.2"}},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPlan(&fakeProvider{}, tt.spec); err == nil {
+				t.Error("want error for invalid spec")
+			}
+		})
+	}
+
+}
@@ -0,0 +1,266 @@
+package recordset
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/rehiy/cloudgo/dns"
+)
+
+const defaultTTL = 600
+
+// Sync zone records to spec, only plan when dryRun
+func Sync(provider dns.DnsProvider, spec *Spec, dryRun bool) (*Result, error) {
+
+	plan, err := NewPlan(provider, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return &Result{Plan: plan}, nil
+	}
+
+	return Apply(provider, plan), nil
+
+}
+
+// Compare zone records with spec and plan create, update and delete actions
+func NewPlan(provider dns.DnsProvider, spec *Spec) (*Plan, error) {
+
+	if spec.Zone == "" {
+		return nil, errors.New("invalid record spec")
+	}
+
+	zone, err := findZone(provider, spec.Zone)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := provider.ListRecords(zone, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &planner{
+		spec:     spec,
+		zone:     zone,
+		existing: map[string][]*dns.Record{},
+		groups:   map[string]bool{},
+		markers:  map[string]*dns.Record{},
+		marked:   map[string]bool{},
+		plan:     &Plan{Spec: spec, Zone: zone, Actions: []*Action{}},
+	}
+
+	keys := []string{}
+	for _, record := range records {
+		name := zone.RelativeName(record.Name)
+		if p.isMarker(name, record) {
+			p.markers[strings.ToLower(name)] = record
+			continue
+		}
+		key := setKey(name, record.Type, record.Line)
+		if _, ok := p.existing[key]; !ok {
+			keys = append(keys, key)
+		}
+		p.existing[key] = append(p.existing[key], record)
+		p.groups[groupKey(name, record.Type)] = true
+	}
+
+	declared := map[string]bool{}
+	for _, set := range spec.Records {
+		if set.Type == "" || len(set.Values) == 0 {
+			return nil, fmt.Errorf("invalid record set: %s %s", set.Name, set.Type)
+		}
+		name := zone.RelativeName(set.Name)
+		key := setKey(name, set.Type, set.Line)
+		if declared[key] {
+			return nil, fmt.Errorf("duplicate record set: %s", key)
+		}
+		declared[key] = true
+		declared[groupKey(name, set.Type)] = true
+		p.planSet(key, name, set)
+	}
+
+	// 删除未声明且归属于本方的记录
+	for _, key := range keys {
+		if declared[key] {
+			continue
+		}
+		current := p.existing[key]
+		name := p.zone.RelativeName(current[0].Name)
+		if !p.owned(name, current[0].Type) {
+			continue
+		}
+		for _, record := range current {
+			p.add(ActionDelete, key, groupKey(name, record.Type), nil, record, false)
+		}
+	}
+
+	// 删除不再使用的归属标记
+	names := []string{}
+	for name := range p.markers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, key := range names {
+		marker := p.markers[key]
+		name, recordType := markerTarget(p.zone.RelativeName(marker.Name))
+		group := groupKey(name, recordType)
+		if declared[group] || !p.owned(name, recordType) {
+			continue
+		}
+		p.add(ActionDelete, group, group, nil, marker, true)
+	}
+
+	p.plan.Actions = append(p.plan.Actions, p.order()...)
+
+	return p.plan, nil
+
+}
+
+// Execute actions of plan, failed actions are collected and do not stop others,
+// ownership markers of a failed record set are skipped
+func Apply(provider dns.DnsProvider, plan *Plan) *Result {
+
+	result := &Result{Plan: plan}
+	failed := map[string]bool{}
+
+	for _, action := range plan.Actions {
+		if action.Marker && failed[action.group] {
+			err := errors.New("skipped after record set failure")
+			result.Errors = append(result.Errors, &ActionError{action, err})
+			continue
+		}
+
+		var err error
+
+		switch action.Type {
+		case ActionCreate:
+			var record *dns.Record
+			if record, err = provider.CreateRecord(plan.Zone, action.Record); err == nil {
+				action.Record = record
+			}
+		case ActionUpdate:
+			var record *dns.Record
+			if record, err = provider.UpdateRecord(plan.Zone, action.Record); err == nil {
+				action.Record = record
+			}
+		case ActionDelete:
+			err = provider.DeleteRecord(plan.Zone, action.Current)
+		}
+
+		if err != nil {
+			failed[action.group] = true
+			result.Errors = append(result.Errors, &ActionError{action, err})
+			continue
+		}
+
+		result.Applied = append(result.Applied, action)
+	}
+
+	return result
+
+}
+
+// 查找区域
+
+func findZone(provider dns.DnsProvider, domain string) (*dns.Zone, error) {
+
+	zones, err := provider.ListZones()
+	if err != nil {
+		return nil, err
+	}
+
+	domain = strings.TrimSuffix(domain, ".")
+	for _, zone := range zones {
+		if strings.EqualFold(zone.Domain, domain) {
+			return zone, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", dns.ZoneDoesNotExistError, domain)
+
+}
+
+// 记录集键，格式为 name type line
+
+func setKey(name string, recordType dns.RecordType, line dns.RecordLine) string {
+
+	if line.IsDefault() {
+		line = dns.RecordLineDEFAULT
+	}
+
+	return strings.ToLower(name) + " " + string(recordType) + " " + string(line)
+
+}
+
+// 归属分组键，格式为 name type
+
+func groupKey(name string, recordType dns.RecordType) string {
+
+	return strings.ToLower(name) + " " + string(recordType)
+
+}
+
+// 归属标记名称，格式为 _owner-<type>.<name>
+
+func markerName(name string, recordType dns.RecordType) string {
+
+	marker := "_owner-" + strings.ToLower(string(recordType))
+	if name != "@" {
+		marker += "." + name
+	}
+
+	return strings.ToLower(marker)
+
+}
+
+// 解析归属标记对应的记录名称和类型
+
+func markerTarget(marker string) (string, dns.RecordType) {
+
+	head, name, ok := strings.Cut(marker, ".")
+	if !ok {
+		name = "@"
+	}
+
+	return name, dns.RecordType(strings.ToUpper(strings.TrimPrefix(head, "_owner-")))
+
+}
+
+// 比对用的记录值
+
+func normalizeValue(recordType dns.RecordType, value string) string {
+
+	switch recordType {
+	case dns.RecordTypeTXT, dns.RecordTypeSPF:
+		if len(value) > 1 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+			value = value[1 : len(value)-1]
+		}
+		return value
+	case dns.RecordTypeCNAME, dns.RecordTypeNS, dns.RecordTypeMX, dns.RecordTypePTR, dns.RecordTypeSRV:
+		return strings.ToLower(strings.TrimSuffix(value, "."))
+	}
+
+	return value
+
+}
+
+// 检查名称是否匹配
+
+func matchNames(patterns []string, name string) bool {
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), strings.ToLower(name)); ok {
+			return true
+		}
+	}
+
+	return false
+
+}
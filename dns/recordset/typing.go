@@ -0,0 +1,95 @@
+package recordset
+
+import (
+	"github.com/rehiy/cloudgo/dns"
+)
+
+// desired records of a zone
+
+type Spec struct {
+	Zone    string       `json:"zone" yaml:"zone"`
+	Owner   *Ownership   `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Records []*RecordSet `json:"records" yaml:"records"`
+}
+
+// records sharing name, type and line, one record per value
+
+type RecordSet struct {
+	Name     string         `json:"name" yaml:"name"`
+	Type     dns.RecordType `json:"type" yaml:"type"`
+	Line     dns.RecordLine `json:"line,omitempty" yaml:"line,omitempty"`
+	TTL      int            `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	Priority int            `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Values   []string       `json:"values" yaml:"values"`
+}
+
+// records the engine may update or delete, nil means never delete undeclared records
+//
+// Marker keeps a TXT record `_owner-<type>.<name>` with value `owner=<Marker>` beside each
+// managed record set. Names is a list of path.Match patterns on relative names.
+// When both are set, a record set must satisfy both.
+
+type Ownership struct {
+	Marker string   `json:"marker,omitempty" yaml:"marker,omitempty"`
+	Names  []string `json:"names,omitempty" yaml:"names,omitempty"`
+}
+
+// kind of planned change
+
+type ActionType string
+
+const (
+	ActionCreate ActionType = "create"
+	ActionUpdate ActionType = "update"
+	ActionDelete ActionType = "delete"
+)
+
+// planned change, Record is the desired state, Current is the existing record
+
+type Action struct {
+	Type    ActionType
+	Key     string
+	Record  *dns.Record
+	Current *dns.Record
+	Marker  bool
+	group   string
+}
+
+// record set left untouched with reason
+
+type Skipped struct {
+	Key    string
+	Reason string
+}
+
+// sync plan, returned as is in dry-run
+
+type Plan struct {
+	Spec    *Spec
+	Zone    *dns.Zone
+	Actions []*Action
+	Skipped []*Skipped
+}
+
+// result of applying plan
+
+type Result struct {
+	Plan    *Plan
+	Applied []*Action
+	Errors  []*ActionError
+}
+
+// failed action with cause
+
+type ActionError struct {
+	Action *Action
+	Err    error
+}
+
+func (e *ActionError) Error() string {
+	return string(e.Action.Type) + " " + e.Action.Key + ": " + e.Err.Error()
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.Err
+}
@@ -11,31 +11,40 @@ type DnsProvider interface {
 	DetailZone(zone *Zone) (*Zone, error)
 
 	// Create a new zone
-	CreateZone(zone *Zone) error
+	CreateZone(zone *Zone) (*Zone, error)
 
 	// Update an existing zone
-	UpdateZone(zone *Zone, record *Record) error
+	UpdateZone(zone *Zone) (*Zone, error)
 
 	// Delete an existing zone
 	DeleteZone(zone *Zone) error
 
-	// List all records in a zone
-	ListRecords(zone *Zone) ([]*Record, error)
+	// List records in a zone, nil filter lists all
+	ListRecords(zone *Zone, filter *RecordFilter) ([]*Record, error)
 
 	// Detail a record in a zone
-	DetailRecord(zone *Zone, record *Record) error
+	DetailRecord(zone *Zone, record *Record) (*Record, error)
 
 	// Create a new record in a zone
-	CreateRecord(zone *Zone, record *Record) error
+	CreateRecord(zone *Zone, record *Record) (*Record, error)
 
 	// Update an existing record in a zone
-	UpdateRecord(zone *Zone, record *Record) error
+	UpdateRecord(zone *Zone, record *Record) (*Record, error)
 
 	// Delete an existing record in a zone
 	DeleteRecord(zone *Zone, record *Record) error
 
-	// List Record Types
+	// Enable or disable a record
+	SetRecordStatus(zone *Zone, record *Record, status RecordStatus) error
+
+	// Set weight of a record for weighted round robin
+	SetRecordWeight(zone *Zone, record *Record, weight int) error
+
+	// List record types supported by the provider
 	ListRecordTypes() ([]RecordType, error)
+
+	// List resolution lines available to a zone
+	ListRecordLines(zone *Zone) ([]*Line, error)
 }
 
 // Zone represents a Dns zone
@@ -59,9 +68,58 @@ type Record struct {
 	Name        string
 	Type        RecordType
 	Value       string
-	Line        string
+	Line        RecordLine
 	TTL         int
 	Priority    int
+	Weight      int
+	Status      RecordStatus
 	Description string
 	Extra       map[string]interface{}
 }
+
+// RecordFilter selects records, empty fields match all
+
+type RecordFilter struct {
+	Name    string // exact name, relative or fully qualified
+	Keyword string // part of relative name
+	Type    RecordType
+	Value   string // exact value
+	Line    RecordLine
+	Status  RecordStatus
+}
+
+// Line represents a resolution line of a provider
+
+type Line struct {
+	Id     string
+	Code   RecordLine
+	Name   string
+	Parent string
+	Extra  map[string]interface{}
+}
+
+// Options of zone migration
+
+type MigrateOpts struct {
+	MinTTL int  // raise lower TTLs to this value, destination zone minimum applies too
+	DryRun bool // only report, nothing is created on destination
+}
+
+// Result of zone migration
+
+type MigrateReport struct {
+	Zone        *Zone // zone on destination
+	Created     []*Record
+	Existing    []*Record
+	Skipped     []*MigrateSkip
+	Failed      []*MigrateSkip
+	Partial     []*MigrateSkip // created, but weight or status was not kept
+	Nameservers []string       // to be set at the registrar
+}
+
+// Record not carried over with reason
+
+type MigrateSkip struct {
+	Record *Record
+	Reason string
+}
@@ -0,0 +1,71 @@
+package dns
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Get fully qualified name of a record in zone, with trailing dot
+func (z *Zone) AbsoluteName(name string) string {
+
+	domain := strings.TrimSuffix(z.Domain, ".")
+	name = strings.TrimSuffix(name, ".")
+
+	if name == "" || name == "@" || strings.EqualFold(name, domain) {
+		return domain + "."
+	}
+
+	if strings.HasSuffix(strings.ToLower(name), "."+strings.ToLower(domain)) {
+		return name + "."
+	}
+
+	return name + "." + domain + "."
+
+}
+
+// Get name of a record relative to zone, apex is returned as @
+func (z *Zone) RelativeName(name string) string {
+
+	domain := strings.TrimSuffix(z.Domain, ".")
+	name = strings.TrimSuffix(name, ".")
+
+	if name == "" || name == "@" || strings.EqualFold(name, domain) {
+		return "@"
+	}
+
+	if suffix := "." + domain; strings.HasSuffix(strings.ToLower(name), strings.ToLower(suffix)) {
+		return name[:len(name)-len(suffix)]
+	}
+
+	return name
+
+}
+
+// Find zone hosting the domain, the longest matching zone wins
+func FindZone(provider DnsProvider, domain string) (*Zone, error) {
+
+	zones, err := provider.ListZones()
+	if err != nil {
+		return nil, err
+	}
+
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	var found *Zone
+	for _, zone := range zones {
+		name := strings.ToLower(strings.TrimSuffix(zone.Domain, "."))
+		if domain != name && !strings.HasSuffix(domain, "."+name) {
+			continue
+		}
+		if found == nil || len(zone.Domain) > len(found.Domain) {
+			found = zone
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s", ZoneDoesNotExistError, domain)
+	}
+
+	return found, nil
+
+}
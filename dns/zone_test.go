@@ -0,0 +1,38 @@
+package dns
+
+import (
+	"testing"
+)
+
+func TestZoneNames(t *testing.T) {
+
+	zone := &Zone{Domain: "example.com."}
+
+	tests := []struct {
+		name     string
+		relative string
+		absolute string
+	}{
+		{"", "@", "example.com."},
+		{"@", "@", "example.com."},
+		{"example.com", "@", "example.com."},
+		{"EXAMPLE.com.", "@", "example.com."},
+		{"www", "www", "www.example.com."},
+		{"www.example.com.", "www", "www.example.com."},
+		{"a.b.Example.COM", "a.b", "a.b.Example.COM."},
+		{"_acme-challenge.www", "_acme-challenge.www", "_acme-challenge.www.example.com."},
+		{"notexample.com", "notexample.com", "notexample.com.example.com."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := zone.RelativeName(tt.name); got != tt.relative {
+				t.Errorf("RelativeName() = %q, want %q", got, tt.relative)
+			}
+			if got := zone.AbsoluteName(tt.name); got != tt.absolute {
+				t.Errorf("AbsoluteName() = %q, want %q", got, tt.absolute)
+			}
+		})
+	}
+
+}
@@ -0,0 +1,204 @@
+package zonefile
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rehiy/cloudgo/dns"
+
+	mdns "github.com/miekg/dns"
+)
+
+const defaultTTL = 600
+
+// Export all records of zone as a RFC 1035 master file
+func Export(provider dns.DnsProvider, zone *dns.Zone) ([]byte, error) {
+
+	detail, err := provider.DetailZone(zone)
+	if err != nil {
+		return nil, err
+	}
+	if detail.Domain == "" {
+		detail.Domain = zone.Domain
+	}
+
+	records, err := provider.ListRecords(zone, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := detail.RelativeName(records[i].Name), detail.RelativeName(records[j].Name)
+		if a != b {
+			return a == "@" || (b != "@" && a < b)
+		}
+		return records[i].Type < records[j].Type
+	})
+
+	ttl := defaultTTL
+	if detail.MinTTL > ttl {
+		ttl = detail.MinTTL
+	}
+
+	buf := &bytes.Buffer{}
+
+	fmt.Fprintf(buf, "$ORIGIN %s\n", origin(detail))
+	fmt.Fprintf(buf, "$TTL %d\n\n", ttl)
+
+	soa := soaRecord(detail, ttl)
+	fmt.Fprintln(buf, formatRR(detail, soa))
+	fmt.Fprintln(buf)
+
+	for _, record := range records {
+		if record.Type == dns.RecordTypeSOA {
+			continue
+		}
+
+		if !record.Line.IsDefault() {
+			fmt.Fprintf(buf, "; line %s: %s %s %s\n", record.Line, record.Name, record.Type, record.Value)
+			continue
+		}
+
+		rr, err := recordRR(detail, record, ttl)
+		if err != nil {
+			fmt.Fprintf(buf, "; %s: %s %s %s\n", err, record.Name, record.Type, record.Value)
+			continue
+		}
+
+		fmt.Fprintln(buf, formatRR(detail, rr))
+	}
+
+	return buf.Bytes(), nil
+
+}
+
+// 生成 SOA 记录
+
+func soaRecord(zone *dns.Zone, ttl int) *mdns.SOA {
+
+	ns := origin(zone)
+	if len(zone.DnsServers) > 0 {
+		ns = absoluteTarget(zone.DnsServers[0])
+	}
+
+	return &mdns.SOA{
+		Hdr: mdns.RR_Header{
+			Name:   origin(zone),
+			Rrtype: mdns.TypeSOA,
+			Class:  mdns.ClassINET,
+			Ttl:    uint32(ttl),
+		},
+		Ns:      ns,
+		Mbox:    "hostmaster." + origin(zone),
+		Serial:  soaSerial(time.Now()),
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  604800,
+		Minttl:  uint32(ttl),
+	}
+
+}
+
+// 按日期生成 SOA 序列号
+
+func soaSerial(t time.Time) uint32 {
+
+	t = t.UTC()
+
+	return uint32(t.Year())*1000000 + uint32(t.Month())*10000 + uint32(t.Day())*100 + uint32(t.Hour())
+
+}
+
+// 转换为资源记录
+
+func recordRR(zone *dns.Zone, record *dns.Record, ttl int) (mdns.RR, error) {
+
+	if record.TTL > 0 {
+		ttl = record.TTL
+	}
+
+	value := record.Value
+
+	switch record.Type {
+	case dns.RecordTypeCNAME, dns.RecordTypeNS, dns.RecordTypePTR, dns.RecordTypeDNAME:
+		value = absoluteTarget(value)
+	case dns.RecordTypeMX:
+		value = fmt.Sprintf("%d %s", record.Priority, absoluteTarget(value))
+	case dns.RecordTypeSRV:
+		fields := strings.Fields(value)
+		if len(fields) == 3 {
+			fields = append([]string{fmt.Sprint(record.Priority)}, fields...)
+		}
+		if len(fields) == 4 {
+			fields[3] = absoluteTarget(fields[3])
+		}
+		value = strings.Join(fields, " ")
+	case dns.RecordTypeTXT, dns.RecordTypeSPF:
+		value = quoteText(value)
+	}
+
+	if _, ok := mdns.StringToType[string(record.Type)]; !ok {
+		return nil, fmt.Errorf("unsupported type")
+	}
+
+	text := fmt.Sprintf("%s %d IN %s %s", zone.AbsoluteName(record.Name), ttl, record.Type, value)
+
+	rr, err := mdns.NewRR(text)
+	if err != nil {
+		return nil, err
+	}
+	if rr == nil {
+		return nil, fmt.Errorf("empty record")
+	}
+
+	return rr, nil
+
+}
+
+// 以相对名称格式化资源记录
+
+func formatRR(zone *dns.Zone, rr mdns.RR) string {
+
+	hdr := rr.Header()
+	rdata := strings.TrimPrefix(rr.String(), hdr.String())
+
+	return fmt.Sprintf(
+		"%s\t%d\tIN\t%s\t%s",
+		zone.RelativeName(hdr.Name), hdr.Ttl, mdns.TypeToString[hdr.Rrtype], rdata,
+	)
+
+}
+
+// 文本记录加引号，超过 255 字节时分段
+
+func quoteText(value string) string {
+
+	if strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) && len(value) > 1 {
+		return value
+	}
+
+	parts := []string{}
+	for len(value) > 255 {
+		parts = append(parts, escapeText(value[:255]))
+		value = value[255:]
+	}
+
+	parts = append(parts, escapeText(value))
+
+	return strings.Join(parts, " ")
+
+}
+
+// 转义文本并加引号
+
+func escapeText(value string) string {
+
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+
+	return `"` + value + `"`
+
+}
@@ -0,0 +1,236 @@
+package zonefile
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rehiy/cloudgo/dns"
+
+	mdns "github.com/miekg/dns"
+)
+
+// Import records from a RFC 1035 master file into zone
+//
+// In merge mode existing records are kept, in replace mode records not present in the
+// file are deleted. Records on non default lines or of provider specific types cannot be
+// expressed in a master file and are always kept. Entries the provider can't represent
+// are listed in the report instead of being imported.
+func Import(provider dns.DnsProvider, zone *dns.Zone, reader io.Reader, mode Mode) (*Report, error) {
+
+	if mode != ModeMERGE && mode != ModeREPLACE {
+		return nil, fmt.Errorf("unknown import mode: %s", mode)
+	}
+
+	types, err := provider.ListRecordTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	supported := map[dns.RecordType]bool{}
+	for _, t := range types {
+		supported[t] = true
+	}
+
+	report := &Report{}
+	wanted := []*dns.Record{}
+
+	zp := mdns.NewZoneParser(reader, origin(zone), "")
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		record := rrRecord(zone, rr)
+		if reason := skipReason(record, supported); reason != "" {
+			report.Skipped = append(report.Skipped, &Skipped{rr.String(), reason})
+			continue
+		}
+		wanted = append(wanted, record)
+	}
+
+	if err := zp.Err(); err != nil {
+		return nil, err
+	}
+
+	existing, err := provider.ListRecords(zone, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make([]bool, len(existing))
+	matched := make([]*dns.Record, len(wanted))
+
+	for i, record := range wanted {
+		for j, item := range existing {
+			if !used[j] && item.Line.IsDefault() && recordKey(zone, item) == recordKey(zone, record) {
+				used[j], matched[i] = true, item
+				break
+			}
+		}
+	}
+
+	if mode == ModeREPLACE {
+		for j, item := range existing {
+			if used[j] || !replaceable(zone, item) {
+				continue
+			}
+			if err := provider.DeleteRecord(zone, item); err != nil {
+				return report, err
+			}
+			report.Deleted = append(report.Deleted, item)
+		}
+	}
+
+	for i, record := range wanted {
+		item := matched[i]
+
+		if item == nil {
+			created, err := provider.CreateRecord(zone, record)
+			if err != nil {
+				return report, err
+			}
+			report.Created = append(report.Created, created)
+			continue
+		}
+
+		if item.TTL == record.TTL {
+			report.Unchanged = append(report.Unchanged, item)
+			continue
+		}
+
+		update := *item
+		update.TTL = record.TTL
+
+		updated, err := provider.UpdateRecord(zone, &update)
+		if err != nil {
+			return report, err
+		}
+		report.Updated = append(report.Updated, updated)
+	}
+
+	return report, nil
+
+}
+
+// 转换资源记录
+
+func rrRecord(zone *dns.Zone, rr mdns.RR) *dns.Record {
+
+	hdr := rr.Header()
+
+	record := &dns.Record{
+		Name: zone.RelativeName(hdr.Name),
+		Type: dns.RecordType(mdns.TypeToString[hdr.Rrtype]),
+		TTL:  int(hdr.Ttl),
+	}
+
+	switch v := rr.(type) {
+	case *mdns.A:
+		record.Value = v.A.String()
+	case *mdns.AAAA:
+		record.Value = v.AAAA.String()
+	case *mdns.CNAME:
+		record.Value = strings.TrimSuffix(v.Target, ".")
+	case *mdns.DNAME:
+		record.Value = strings.TrimSuffix(v.Target, ".")
+	case *mdns.NS:
+		record.Value = strings.TrimSuffix(v.Ns, ".")
+	case *mdns.PTR:
+		record.Value = strings.TrimSuffix(v.Ptr, ".")
+	case *mdns.MX:
+		record.Priority = int(v.Preference)
+		record.Value = strings.TrimSuffix(v.Mx, ".")
+	case *mdns.SRV:
+		record.Priority = int(v.Priority)
+		record.Value = fmt.Sprintf("%d %d %d %s", v.Priority, v.Weight, v.Port, strings.TrimSuffix(v.Target, "."))
+	case *mdns.TXT:
+		record.Value = unescapeText(v.Txt)
+	case *mdns.SPF:
+		record.Value = unescapeText(v.Txt)
+	default:
+		record.Value = strings.TrimPrefix(rr.String(), hdr.String())
+	}
+
+	return record
+
+}
+
+// 合并文本分段并去除转义
+
+func unescapeText(txt []string) string {
+
+	value := strings.Join(txt, "")
+	value = strings.ReplaceAll(value, `\"`, `"`)
+	value = strings.ReplaceAll(value, `\\`, `\`)
+
+	return value
+
+}
+
+// 检查记录能否导入
+
+func skipReason(record *dns.Record, supported map[dns.RecordType]bool) string {
+
+	if record.Type == dns.RecordTypeSOA {
+		return "SOA is managed by provider"
+	}
+
+	if record.Type == dns.RecordTypeNS && record.Name == "@" {
+		return "apex NS is managed by provider"
+	}
+
+	if !supported[record.Type] {
+		return "record type not supported by provider"
+	}
+
+	return ""
+
+}
+
+// 检查记录在替换模式下能否删除
+
+func replaceable(zone *dns.Zone, record *dns.Record) bool {
+
+	if !record.Line.IsDefault() {
+		return false
+	}
+
+	if record.Type == dns.RecordTypeSOA {
+		return false
+	}
+
+	if record.Type == dns.RecordTypeNS && zone.RelativeName(record.Name) == "@" {
+		return false
+	}
+
+	_, ok := mdns.StringToType[string(record.Type)]
+
+	return ok
+
+}
+
+// 记录比对键
+
+func recordKey(zone *dns.Zone, record *dns.Record) string {
+
+	value := strings.TrimSuffix(record.Value, ".")
+
+	switch record.Type {
+	case dns.RecordTypeMX:
+		value = fmt.Sprintf("%d %s", record.Priority, strings.ToLower(value))
+	case dns.RecordTypeCNAME, dns.RecordTypeNS, dns.RecordTypePTR, dns.RecordTypeDNAME:
+		value = strings.ToLower(value)
+	case dns.RecordTypeSRV:
+		// 部分平台的记录值不含优先级
+		fields := strings.Fields(value)
+		if len(fields) == 3 {
+			fields = append([]string{fmt.Sprint(record.Priority)}, fields...)
+		}
+		if len(fields) == 4 {
+			fields[3] = strings.ToLower(strings.TrimSuffix(fields[3], "."))
+		}
+		value = strings.Join(fields, " ")
+	}
+
+	name := strings.ToLower(zone.RelativeName(record.Name))
+
+	return name + "\t" + string(record.Type) + "\t" + value
+
+}
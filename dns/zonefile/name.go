@@ -0,0 +1,27 @@
+package zonefile
+
+import (
+	"strings"
+
+	"github.com/rehiy/cloudgo/dns"
+)
+
+// 区域根域名，以点结尾
+
+func origin(zone *dns.Zone) string {
+
+	return strings.TrimSuffix(zone.Domain, ".") + "."
+
+}
+
+// 转换为绝对目标地址
+
+func absoluteTarget(value string) string {
+
+	if value == "" || value == "." || strings.HasSuffix(value, ".") {
+		return value
+	}
+
+	return value + "."
+
+}
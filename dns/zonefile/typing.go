@@ -0,0 +1,31 @@
+package zonefile
+
+import (
+	"github.com/rehiy/cloudgo/dns"
+)
+
+// Import mode
+
+type Mode string
+
+const (
+	ModeMERGE   Mode = "merge"
+	ModeREPLACE Mode = "replace"
+)
+
+// Result of an import
+
+type Report struct {
+	Created   []*dns.Record
+	Updated   []*dns.Record
+	Deleted   []*dns.Record
+	Unchanged []*dns.Record
+	Skipped   []*Skipped
+}
+
+// Entry not representable by the target provider
+
+type Skipped struct {
+	Entry  string
+	Reason string
+}
@@ -0,0 +1,258 @@
+package zonefile
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/rehiy/cloudgo/dns"
+)
+
+// 内存平台，记录按 Id 保存
+type memProvider struct {
+	dns.DnsProvider
+	zone    *dns.Zone
+	records []*dns.Record
+	nextId  int
+}
+
+func (p *memProvider) DetailZone(zone *dns.Zone) (*dns.Zone, error) {
+
+	detail := *p.zone
+	return &detail, nil
+
+}
+
+func (p *memProvider) ListRecords(zone *dns.Zone, filter *dns.RecordFilter) ([]*dns.Record, error) {
+
+	records := []*dns.Record{}
+	for _, record := range p.records {
+		if filter.Match(zone, record) {
+			item := *record
+			records = append(records, &item)
+		}
+	}
+
+	return records, nil
+
+}
+
+func (p *memProvider) CreateRecord(zone *dns.Zone, record *dns.Record) (*dns.Record, error) {
+
+	p.nextId++
+
+	item := *record
+	item.Id = fmt.Sprint(p.nextId)
+	p.records = append(p.records, &item)
+
+	created := item
+	return &created, nil
+
+}
+
+func (p *memProvider) UpdateRecord(zone *dns.Zone, record *dns.Record) (*dns.Record, error) {
+
+	for i, item := range p.records {
+		if item.Id == record.Id {
+			update := *record
+			p.records[i] = &update
+			return record, nil
+		}
+	}
+
+	return nil, dns.RecordDoesNotExistError
+
+}
+
+func (p *memProvider) DeleteRecord(zone *dns.Zone, record *dns.Record) error {
+
+	for i, item := range p.records {
+		if item.Id == record.Id {
+			p.records = append(p.records[:i], p.records[i+1:]...)
+			return nil
+		}
+	}
+
+	return dns.RecordDoesNotExistError
+
+}
+
+func (p *memProvider) ListRecordTypes() ([]dns.RecordType, error) {
+
+	return []dns.RecordType{
+		dns.RecordTypeA, dns.RecordTypeAAAA, dns.RecordTypeCNAME, dns.RecordTypeMX,
+		dns.RecordTypeNS, dns.RecordTypeSRV, dns.RecordTypeTXT, dns.RecordTypeCAA,
+	}, nil
+
+}
+
+func newMemProvider(records ...*dns.Record) *memProvider {
+
+	p := &memProvider{zone: &dns.Zone{Id: "z1", Domain: "example.com", DnsServers: []string{"ns1.dns.test"}}}
+	for _, record := range records {
+		p.CreateRecord(p.zone, record)
+	}
+
+	return p
+
+}
+
+// 记录摘要，忽略 Id 便于比较
+func recordKeys(zone *dns.Zone, records []*dns.Record) []string {
+
+	keys := []string{}
+	for _, record := range records {
+		keys = append(keys, fmt.Sprintf("%s %d", recordKey(zone, record), record.TTL))
+	}
+	sort.Strings(keys)
+
+	return keys
+
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+
+	longText := "v=DKIM1; k=rsa; p=" + strings.Repeat("A", 300) + `; note="quoted" \ end`
+
+	portable := []*dns.Record{
+		{Name: "@", Type: dns.RecordTypeA, Value: "192.0.2.1", TTL: 600},
+		{Name: "@", Type: dns.RecordTypeA, Value: "192.0.2.2", TTL: 600},
+		{Name: "www", Type: dns.RecordTypeCNAME, Value: "example.com", TTL: 300},
+		{Name: "v6", Type: dns.RecordTypeAAAA, Value: "2001:db8::1", TTL: 600},
+		{Name: "@", Type: dns.RecordTypeMX, Value: "mx1.example.com", Priority: 10, TTL: 3600},
+		{Name: "@", Type: dns.RecordTypeMX, Value: "mx2.example.net.", Priority: 20, TTL: 3600},
+		{Name: "mail._domainkey", Type: dns.RecordTypeTXT, Value: longText, TTL: 600},
+		{Name: "_sip._tcp", Type: dns.RecordTypeSRV, Value: "5 5060 sip.example.com", Priority: 10, TTL: 600},
+		{Name: "sub", Type: dns.RecordTypeNS, Value: "ns.other.test", TTL: 86400},
+		{Name: "@", Type: dns.RecordTypeCAA, Value: `0 issue "letsencrypt.org"`, TTL: 600},
+	}
+
+	records := append([]*dns.Record{
+		{Name: "@", Type: dns.RecordTypeNS, Value: "ns1.dns.test", TTL: 86400},
+		{Name: "@", Type: dns.RecordTypeSOA, Value: "ns1.dns.test. hostmaster.example.com. 1 3600 600 604800 600", TTL: 600},
+		{Name: "www", Type: dns.RecordTypeA, Value: "192.0.2.9", Line: dns.RecordLineTELECOM, TTL: 600},
+	}, portable...)
+
+	src := newMemProvider(records...)
+
+	data, err := Export(src, src.zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Contains(data, []byte("$ORIGIN example.com.\n")) {
+		t.Errorf("missing origin:\n%s", data)
+	}
+	if !bytes.Contains(data, []byte("; line telecom: www A 192.0.2.9")) {
+		t.Errorf("line record not commented:\n%s", data)
+	}
+
+	dst := newMemProvider()
+
+	report, err := Import(dst, dst.zone, bytes.NewReader(data), ModeMERGE)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := recordKeys(dst.zone, dst.records), recordKeys(src.zone, portable); !reflect.DeepEqual(got, want) {
+		t.Errorf("imported records:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	for _, record := range dst.records {
+		if record.Type == dns.RecordTypeTXT && record.Value != longText {
+			t.Errorf("txt value = %q, want %q", record.Value, longText)
+		}
+	}
+
+	if len(report.Created) != len(portable) {
+		t.Errorf("created %d records, want %d", len(report.Created), len(portable))
+	}
+
+	// 导出文件包含 SOA 和根域 NS，导入时跳过
+	if len(report.Skipped) != 2 {
+		t.Errorf("skipped = %+v, want SOA and apex NS", report.Skipped)
+	}
+
+	// 再次导入不产生变更
+	again, err := Import(dst, dst.zone, bytes.NewReader(data), ModeMERGE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(again.Created) != 0 || len(again.Updated) != 0 || len(again.Unchanged) != len(portable) {
+		t.Errorf("second import created %d, updated %d, unchanged %d", len(again.Created), len(again.Updated), len(again.Unchanged))
+	}
+
+}
+
+func TestImportModes(t *testing.T) {
+
+	file := strings.Join([]string{
+		"$ORIGIN example.com.",
+		"$TTL 600",
+		"@ IN A 192.0.2.1",
+		"www 300 IN CNAME example.com.",
+		"",
+	}, "\n")
+
+	existing := func() *memProvider {
+		return newMemProvider(
+			&dns.Record{Name: "@", Type: dns.RecordTypeA, Value: "192.0.2.1", TTL: 600},
+			&dns.Record{Name: "www", Type: dns.RecordTypeCNAME, Value: "example.com", TTL: 600},
+			&dns.Record{Name: "old", Type: dns.RecordTypeA, Value: "192.0.2.5", TTL: 600},
+			&dns.Record{Name: "old", Type: dns.RecordTypeA, Value: "192.0.2.6", Line: dns.RecordLineUNICOM, TTL: 600},
+			&dns.Record{Name: "@", Type: dns.RecordTypeNS, Value: "ns1.dns.test", TTL: 86400},
+		)
+	}
+
+	tests := []struct {
+		mode      Mode
+		unchanged int
+		updated   int
+		deleted   []string
+		remaining int
+	}{
+		{ModeMERGE, 1, 1, nil, 5},
+		{ModeREPLACE, 1, 1, []string{"old A 192.0.2.5"}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			provider := existing()
+
+			report, err := Import(provider, provider.zone, strings.NewReader(file), tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(report.Unchanged) != tt.unchanged || len(report.Updated) != tt.updated || len(report.Created) != 0 {
+				t.Errorf("unchanged %d, updated %d, created %d", len(report.Unchanged), len(report.Updated), len(report.Created))
+			}
+
+			deleted := []string{}
+			for _, record := range report.Deleted {
+				deleted = append(deleted, fmt.Sprintf("%s %s %s", record.Name, record.Type, record.Value))
+			}
+			if len(deleted) != len(tt.deleted) || (len(deleted) > 0 && !reflect.DeepEqual(deleted, tt.deleted)) {
+				t.Errorf("deleted = %v, want %v", deleted, tt.deleted)
+			}
+
+			if len(provider.records) != tt.remaining {
+				t.Errorf("%d records remain, want %d", len(provider.records), tt.remaining)
+			}
+		})
+	}
+
+}
+
+func TestImportUnknownMode(t *testing.T) {
+
+	provider := newMemProvider()
+
+	if _, err := Import(provider, provider.zone, strings.NewReader(""), Mode("sync")); err == nil {
+		t.Error("want error for unknown mode")
+	}
+
+}
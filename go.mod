@@ -7,23 +7,30 @@ require (
 	github.com/alibabacloud-go/alidns-20150109/v4 v4.0.7
 	github.com/alibabacloud-go/darabonba-openapi/v2 v2.0.4
 	github.com/alibabacloud-go/ecs-20140526/v3 v3.0.7
+	github.com/alibabacloud-go/openapi-util v0.1.0
 	github.com/alibabacloud-go/swas-open-20200601 v1.0.4
 	github.com/alibabacloud-go/tea v1.2.1
 	github.com/alibabacloud-go/tea-utils/v2 v2.0.3
 	// Cloudflare
 	github.com/cloudflare/cloudflare-go v0.72.0
+	// DNS
+	github.com/miekg/dns v1.1.55
 	// Tencent Cloud
+	github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cbs v1.0.700
 	github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common v1.0.700
 	github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cvm v1.0.700
 	github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/dnspod v1.0.700
 	github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/lighthouse v1.0.700
+	github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/vpc v1.0.700
+	// Others
+	golang.org/x/crypto v0.11.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/alibabacloud-go/alibabacloud-gateway-spi v0.0.4 // indirect
 	github.com/alibabacloud-go/debug v1.0.0 // indirect
 	github.com/alibabacloud-go/endpoint-util v1.1.1 // indirect
-	github.com/alibabacloud-go/openapi-util v0.1.0 // indirect
 	github.com/alibabacloud-go/tea-utils v1.4.5 // indirect
 	github.com/alibabacloud-go/tea-xml v1.1.3 // indirect
 	github.com/aliyun/credentials-go v1.3.0 // indirect
@@ -34,10 +41,12 @@ require (
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v1.0.2 // indirect
-	github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cbs v1.0.700
 	github.com/tjfoc/gmsm v1.4.1 // indirect
+	golang.org/x/mod v0.8.0 // indirect
 	golang.org/x/net v0.12.0 // indirect
+	golang.org/x/sys v0.10.0 // indirect
 	golang.org/x/text v0.11.0 // indirect
 	golang.org/x/time v0.3.0 // indirect
+	golang.org/x/tools v0.6.0 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
 )
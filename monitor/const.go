@@ -0,0 +1,33 @@
+package monitor
+
+// Metric Name constants, usage in percent and network in Mbps
+
+type MetricName string
+
+const (
+	MetricCpuUsage       MetricName = "cpu_usage"
+	MetricMemoryUsage    MetricName = "memory_usage"
+	MetricDiskUsage      MetricName = "disk_usage"
+	MetricNetIn          MetricName = "net_in"
+	MetricNetOut         MetricName = "net_out"
+	MetricBandwidthUsage MetricName = "bandwidth_usage"
+)
+
+// Metric Unit constants
+
+const (
+	UnitPercent = "%"
+	UnitMbps    = "Mbps"
+)
+
+// Monitor Error
+
+type MonitorError string
+
+const (
+	MetricNotSupportedError MonitorError = "MetricNotSupportedError"
+)
+
+func (e MonitorError) Error() string {
+	return string(e)
+}
@@ -0,0 +1,131 @@
+package drivers
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/rehiy/cloudgo/compute"
+	"github.com/rehiy/cloudgo/monitor"
+	"github.com/rehiy/cloudgo/provider"
+	"github.com/rehiy/cloudgo/provider/alibaba"
+)
+
+// 云监控 https://help.aliyun.com/document_detail/51936.html
+
+var ecsMetricNames = map[monitor.MetricName]string{
+	monitor.MetricCpuUsage:       "CPUUtilization",
+	monitor.MetricMemoryUsage:    "memory_usedutilization",
+	monitor.MetricDiskUsage:      "diskusage_utilization",
+	monitor.MetricNetIn:          "InternetInRate",
+	monitor.MetricNetOut:         "InternetOutRate",
+	monitor.MetricBandwidthUsage: "InternetOutRate_Percent",
+}
+
+type AlibabaMonitorDriver struct {
+	client *alibaba.Client
+	rq     *provider.ReqeustParam
+}
+
+func NewAlibabaMonitorDriver(rq *provider.ReqeustParam) *AlibabaMonitorDriver {
+
+	client := alibaba.NewClient(rq)
+
+	return &AlibabaMonitorDriver{client, rq}
+
+}
+
+// Get time series of metrics between start and end, period in seconds
+func (p *AlibabaMonitorDriver) GetNodeMetrics(node *compute.Node, names []monitor.MetricName, start, end time.Time, period int) ([]*monitor.MetricSeries, error) {
+
+	list := []*monitor.MetricSeries{}
+
+	for _, name := range names {
+		metric, ok := ecsMetricNames[name]
+		if !ok {
+			return nil, monitor.MetricNotSupportedError
+		}
+
+		series, err := p.getMetric(node, metric, start, end, period)
+		if err != nil {
+			return nil, err
+		}
+
+		// 网络速率单位为 bit/s
+		if name == monitor.MetricNetIn || name == monitor.MetricNetOut {
+			for _, point := range series.Points {
+				point.Value = point.Value / 1000 / 1000
+			}
+		}
+
+		series.Name, series.Unit = name, name.Unit()
+		list = append(list, series)
+	}
+
+	return list, nil
+
+}
+
+// 查询单个指标
+func (p *AlibabaMonitorDriver) getMetric(node *compute.Node, metric string, start, end time.Time, period int) (*monitor.MetricSeries, error) {
+
+	dimensions, _ := json.Marshal([]map[string]string{{"instanceId": node.Id}})
+
+	payload := map[string]any{
+		"Namespace":  "acs_ecs_dashboard",
+		"MetricName": metric,
+		"Period":     strconv.Itoa(period),
+		"StartTime":  strconv.FormatInt(start.UnixMilli(), 10),
+		"EndTime":    strconv.FormatInt(end.UnixMilli(), 10),
+		"Dimensions": string(dimensions),
+		"Length":     "1000",
+	}
+
+	series := &monitor.MetricSeries{
+		NodeId: node.Id,
+		Period: period,
+		Points: []*monitor.MetricPoint{},
+		Extra:  map[string]interface{}{"Namespace": "acs_ecs_dashboard", "MetricName": metric},
+	}
+
+	for {
+		result := struct {
+			Code       string
+			Message    string
+			NextToken  string
+			Datapoints string
+		}{}
+
+		// 云监控接口域名为 metrics.<region>.aliyuncs.com
+		if err := p.client.Request("metrics", "2019-01-01", "DescribeMetricList", payload, &result); err != nil {
+			return nil, err
+		}
+
+		points := []struct {
+			Timestamp int64
+			Average   float64
+		}{}
+
+		if result.Datapoints != "" {
+			if err := json.Unmarshal([]byte(result.Datapoints), &points); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, point := range points {
+			series.Points = append(series.Points, &monitor.MetricPoint{
+				Time:  time.UnixMilli(point.Timestamp),
+				Value: point.Average,
+			})
+		}
+
+		if result.NextToken == "" {
+			break
+		}
+
+		payload["NextToken"] = result.NextToken
+	}
+
+	return series, nil
+
+}
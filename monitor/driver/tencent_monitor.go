@@ -0,0 +1,138 @@
+package drivers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rehiy/cloudgo/compute"
+	"github.com/rehiy/cloudgo/monitor"
+	"github.com/rehiy/cloudgo/provider"
+	"github.com/rehiy/cloudgo/provider/tencent"
+)
+
+// 云监控 https://cloud.tencent.com/document/api/248/31014
+
+var cvmMetricNames = map[monitor.MetricName]string{
+	monitor.MetricCpuUsage:    "CpuUsage",
+	monitor.MetricMemoryUsage: "MemUsage",
+	monitor.MetricDiskUsage:   "CvmDiskUsage",
+	monitor.MetricNetIn:       "WanIntraffic",
+	monitor.MetricNetOut:      "WanOuttraffic",
+}
+
+var lighthouseMetricNames = map[monitor.MetricName]string{
+	monitor.MetricCpuUsage:    "CpuUsage",
+	monitor.MetricMemoryUsage: "MemUsage",
+	monitor.MetricDiskUsage:   "LighthouseDiskUsage",
+	monitor.MetricNetIn:       "LighthouseIntraffic",
+	monitor.MetricNetOut:      "LighthouseOuttraffic",
+}
+
+type TencentMonitorDriver struct {
+	client *tencent.Client
+	rq     *provider.ReqeustParam
+}
+
+func NewTencentMonitorDriver(rq *provider.ReqeustParam) *TencentMonitorDriver {
+
+	client := tencent.NewClient(rq)
+
+	return &TencentMonitorDriver{client, rq}
+
+}
+
+// Get time series of metrics between start and end, period in seconds
+// Lighthouse instances (lhins-) use QCE/LIGHTHOUSE, others use QCE/CVM
+func (p *TencentMonitorDriver) GetNodeMetrics(node *compute.Node, names []monitor.MetricName, start, end time.Time, period int) ([]*monitor.MetricSeries, error) {
+
+	namespace, metrics := "QCE/CVM", cvmMetricNames
+	if strings.HasPrefix(node.Id, "lhins-") {
+		namespace, metrics = "QCE/LIGHTHOUSE", lighthouseMetricNames
+	}
+
+	list := []*monitor.MetricSeries{}
+
+	for _, name := range names {
+		// 带宽使用率由出流量和带宽上限计算
+		if name == monitor.MetricBandwidthUsage {
+			if node.Bandwidth <= 0 {
+				return nil, monitor.MetricNotSupportedError
+			}
+			series, err := p.getMetric(node, namespace, metrics[monitor.MetricNetOut], start, end, period)
+			if err != nil {
+				return nil, err
+			}
+			for _, point := range series.Points {
+				point.Value = point.Value * 100 / float64(node.Bandwidth)
+			}
+			series.Name, series.Unit = name, name.Unit()
+			list = append(list, series)
+			continue
+		}
+
+		metric, ok := metrics[name]
+		if !ok {
+			return nil, monitor.MetricNotSupportedError
+		}
+
+		series, err := p.getMetric(node, namespace, metric, start, end, period)
+		if err != nil {
+			return nil, err
+		}
+
+		series.Name, series.Unit = name, name.Unit()
+		list = append(list, series)
+	}
+
+	return list, nil
+
+}
+
+// 查询单个指标
+func (p *TencentMonitorDriver) getMetric(node *compute.Node, namespace, metric string, start, end time.Time, period int) (*monitor.MetricSeries, error) {
+
+	payload := map[string]any{
+		"Namespace":  namespace,
+		"MetricName": metric,
+		"Period":     period,
+		"StartTime":  start.Format(time.RFC3339),
+		"EndTime":    end.Format(time.RFC3339),
+		"Instances": []map[string]any{
+			{"Dimensions": []map[string]string{{"Name": "InstanceId", "Value": node.Id}}},
+		},
+	}
+
+	result := struct {
+		Period     int
+		DataPoints []struct {
+			Timestamps []float64
+			Values     []float64
+		}
+	}{}
+
+	if err := p.client.Request("monitor", "2018-07-24", "GetMonitorData", payload, &result); err != nil {
+		return nil, err
+	}
+
+	series := &monitor.MetricSeries{
+		NodeId: node.Id,
+		Period: result.Period,
+		Points: []*monitor.MetricPoint{},
+		Extra:  map[string]interface{}{"Namespace": namespace, "MetricName": metric},
+	}
+
+	for _, data := range result.DataPoints {
+		for i, stamp := range data.Timestamps {
+			if i >= len(data.Values) {
+				break
+			}
+			series.Points = append(series.Points, &monitor.MetricPoint{
+				Time:  time.Unix(int64(stamp), 0),
+				Value: data.Values[i],
+			})
+		}
+	}
+
+	return series, nil
+
+}
@@ -0,0 +1,44 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/rehiy/cloudgo/compute"
+)
+
+// Define Monitor Provider interface for node metrics
+
+type MonitorProvider interface {
+	// Get time series of metrics between start and end, period in seconds
+	GetNodeMetrics(node *compute.Node, names []MetricName, start, end time.Time, period int) ([]*MetricSeries, error)
+}
+
+// time series of one metric for node
+
+type MetricSeries struct {
+	NodeId string
+	Name   MetricName
+	Unit   string
+	Period int
+	Points []*MetricPoint
+	Extra  map[string]interface{}
+}
+
+// value at a point of time
+
+type MetricPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// Get unit of metric
+func (n MetricName) Unit() string {
+
+	switch n {
+	case MetricNetIn, MetricNetOut:
+		return UnitMbps
+	}
+
+	return UnitPercent
+
+}
@@ -0,0 +1,63 @@
+package network
+
+import (
+	"net"
+)
+
+// Split cidr into subnets with given prefix length, e.g. 10.0.0.0/16 into /24
+func SplitCidr(cidr string, prefix int) ([]string, error) {
+
+	_, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	ones, bits := ipnet.Mask.Size()
+	if bits != 32 || prefix < ones || prefix > bits || prefix-ones > 16 {
+		return nil, CidrError
+	}
+
+	base := ipToUint(ipnet.IP.To4())
+	size := uint32(1) << uint(bits-prefix)
+	count := 1 << uint(prefix-ones)
+
+	subnets := []string{}
+	for i := 0; i < count; i++ {
+		ip := uintToIp(base + uint32(i)*size)
+		subnets = append(subnets, (&net.IPNet{IP: ip, Mask: net.CIDRMask(prefix, bits)}).String())
+	}
+
+	return subnets, nil
+
+}
+
+// Check whether two cidr blocks overlap
+func CidrOverlap(a, b string) (bool, error) {
+
+	_, na, err := net.ParseCIDR(a)
+	if err != nil {
+		return false, err
+	}
+
+	_, nb, err := net.ParseCIDR(b)
+	if err != nil {
+		return false, err
+	}
+
+	return na.Contains(nb.IP) || nb.Contains(na.IP), nil
+
+}
+
+// 地址转换为整数
+func ipToUint(ip net.IP) uint32 {
+
+	return uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+
+}
+
+// 整数转换为地址
+func uintToIp(n uint32) net.IP {
+
+	return net.IPv4(byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+
+}
@@ -0,0 +1,92 @@
+package network
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitCidr(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		cidr    string
+		prefix  int
+		want    []string
+		wantErr error
+	}{
+		{"same prefix", "10.0.0.0/24", 24, []string{"10.0.0.0/24"}, nil},
+		{"into halves", "10.0.0.0/24", 25, []string{"10.0.0.0/25", "10.0.0.128/25"}, nil},
+		{"into quarters", "192.168.0.0/22", 24, []string{"192.168.0.0/24", "192.168.1.0/24", "192.168.2.0/24", "192.168.3.0/24"}, nil},
+		{"host bits are masked", "10.0.0.77/30", 31, []string{"10.0.0.76/31", "10.0.0.78/31"}, nil},
+		{"prefix shorter than block", "10.0.0.0/24", 16, nil, CidrError},
+		{"prefix longer than 32", "10.0.0.0/24", 33, nil, CidrError},
+		{"too many subnets", "10.0.0.0/8", 25, nil, CidrError},
+		{"ipv6 not supported", "fd00::/64", 72, nil, CidrError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitCidr(tt.cidr, tt.prefix)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitCidr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
+
+func TestSplitCidrCount(t *testing.T) {
+
+	subnets, err := SplitCidr("10.0.0.0/16", 24)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(subnets) != 256 || subnets[255] != "10.0.255.0/24" {
+		t.Errorf("got %d subnets, last %s", len(subnets), subnets[len(subnets)-1])
+	}
+
+}
+
+func TestSplitCidrInvalid(t *testing.T) {
+
+	if _, err := SplitCidr("10.0.0.0", 24); err == nil {
+		t.Error("want error for address without prefix")
+	}
+
+}
+
+func TestCidrOverlap(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		a, b    string
+		want    bool
+		wantErr bool
+	}{
+		{"same block", "10.0.0.0/16", "10.0.0.0/16", true, false},
+		{"a contains b", "10.0.0.0/16", "10.0.5.0/24", true, false},
+		{"b contains a", "10.0.5.0/24", "10.0.0.0/8", true, false},
+		{"adjacent", "10.0.0.0/24", "10.0.1.0/24", false, false},
+		{"disjoint", "10.0.0.0/16", "192.168.0.0/16", false, false},
+		{"invalid a", "10.0.0.0/33", "10.0.0.0/16", false, true},
+		{"invalid b", "10.0.0.0/16", "vpc", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CidrOverlap(tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("CidrOverlap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+}
@@ -0,0 +1,39 @@
+package network
+
+// Network State constants
+
+type NetworkState string
+
+const (
+	NetworkStateAVAILABLE NetworkState = "available"
+	NetworkStatePENDING   NetworkState = "pending"
+	NetworkStateUNKNOWN   NetworkState = "unknown"
+)
+
+// Route Next Hop constants
+
+type NextHopType string
+
+const (
+	NextHopLOCAL    NextHopType = "local"
+	NextHopINSTANCE NextHopType = "instance"
+	NextHopENI      NextHopType = "eni"
+	NextHopHAVIP    NextHopType = "havip"
+	NextHopNAT      NextHopType = "nat"
+	NextHopVPN      NextHopType = "vpn"
+	NextHopPEERING  NextHopType = "peering"
+	NextHopUNKNOWN  NextHopType = "unknown"
+)
+
+// Network Error
+
+type NetworkError string
+
+const (
+	CidrError         NetworkError = "CidrError"
+	NotSupportedError NetworkError = "NotSupportedError"
+)
+
+func (e NetworkError) Error() string {
+	return string(e)
+}
@@ -0,0 +1,566 @@
+package drivers
+
+import (
+	"time"
+
+	"github.com/rehiy/cloudgo/network"
+	"github.com/rehiy/cloudgo/provider"
+	"github.com/rehiy/cloudgo/provider/alibaba"
+)
+
+// 专有网络 https://help.aliyun.com/document_detail/35737.html
+
+var alibabaNetworkStates = map[string]network.NetworkState{
+	"Available": network.NetworkStateAVAILABLE,
+	"Pending":   network.NetworkStatePENDING,
+}
+
+var alibabaNextHopTypes = map[network.NextHopType]string{
+	network.NextHopLOCAL:    "local",
+	network.NextHopINSTANCE: "Instance",
+	network.NextHopENI:      "NetworkInterface",
+	network.NextHopHAVIP:    "HaVip",
+	network.NextHopNAT:      "NatGateway",
+	network.NextHopVPN:      "VpnGateway",
+	network.NextHopPEERING:  "VpcPeer",
+}
+
+type alibabaVpc struct {
+	VpcId         string
+	VpcName       string
+	CidrBlock     string
+	Ipv6CidrBlock string
+	Status        string
+	IsDefault     bool
+	CreationTime  string
+	VRouterId     string
+	Description   string
+}
+
+type alibabaVSwitch struct {
+	VSwitchId               string
+	VSwitchName             string
+	VpcId                   string
+	ZoneId                  string
+	CidrBlock               string
+	AvailableIpAddressCount int
+	Status                  string
+	IsDefault               bool
+	CreationTime            string
+	Description             string
+	RouteTable              struct {
+		RouteTableId string
+	}
+}
+
+type alibabaRouteTable struct {
+	RouteTableId   string
+	RouteTableName string
+	RouteTableType string
+	VpcId          string
+	CreationTime   string
+	VSwitchIds     struct {
+		VSwitchId []string
+	}
+}
+
+type alibabaRouteEntry struct {
+	RouteEntryId         string
+	DestinationCidrBlock string
+	Type                 string
+	Status               string
+	Description          string
+	NextHops             struct {
+		NextHop []struct {
+			NextHopType string
+			NextHopId   string
+		}
+	}
+}
+
+type AlibabaVpcDriver struct {
+	client *alibaba.Client
+	rq     *provider.ReqeustParam
+}
+
+func NewAlibabaVpcDriver(rq *provider.ReqeustParam) *AlibabaVpcDriver {
+
+	client := alibaba.NewClient(rq)
+
+	return &AlibabaVpcDriver{client, rq}
+
+}
+
+// List all vpcs
+func (p *AlibabaVpcDriver) ListVpcs() ([]*network.Vpc, error) {
+
+	payload := map[string]any{
+		"RegionId":   p.rq.RegionId,
+		"PageNumber": 1,
+		"PageSize":   50,
+	}
+
+	vpcs := []*network.Vpc{}
+
+	for {
+		result := struct {
+			TotalCount int
+			Vpcs       struct {
+				Vpc []*alibabaVpc
+			}
+		}{}
+
+		if err := p.request("DescribeVpcs", payload, &result); err != nil {
+			return nil, err
+		}
+
+		for _, item := range result.Vpcs.Vpc {
+			vpcs = append(vpcs, p.toVpc(item))
+		}
+
+		if len(vpcs) >= result.TotalCount || len(result.Vpcs.Vpc) == 0 {
+			break
+		}
+
+		payload["PageNumber"] = payload["PageNumber"].(int) + 1
+	}
+
+	return vpcs, nil
+
+}
+
+// Create a new vpc
+func (p *AlibabaVpcDriver) CreateVpc(opts *network.VpcCreateOpts) (*network.Vpc, error) {
+
+	payload := map[string]any{
+		"RegionId":    p.rq.RegionId,
+		"VpcName":     opts.Name,
+		"CidrBlock":   opts.CidrBlock,
+		"Description": opts.Description,
+	}
+
+	result := struct {
+		VpcId        string
+		VRouterId    string
+		RouteTableId string
+	}{}
+
+	if err := p.request("CreateVpc", payload, &result); err != nil {
+		return nil, err
+	}
+
+	item := &network.Vpc{
+		Id:        result.VpcId,
+		Name:      opts.Name,
+		CidrBlock: opts.CidrBlock,
+		State:     network.NetworkStatePENDING,
+		CreatedAt: time.Now(),
+		Extra: map[string]interface{}{
+			"VRouterId":    result.VRouterId,
+			"RouteTableId": result.RouteTableId,
+		},
+	}
+
+	return item, nil
+
+}
+
+// Delete an existing vpc
+func (p *AlibabaVpcDriver) DeleteVpc(item *network.Vpc) error {
+
+	payload := map[string]any{
+		"RegionId": p.rq.RegionId,
+		"VpcId":    item.Id,
+	}
+
+	return p.request("DeleteVpc", payload, nil)
+
+}
+
+// List all subnets in a vpc
+func (p *AlibabaVpcDriver) ListSubnets(item *network.Vpc) ([]*network.Subnet, error) {
+
+	payload := map[string]any{
+		"RegionId":   p.rq.RegionId,
+		"VpcId":      item.Id,
+		"PageNumber": 1,
+		"PageSize":   50,
+	}
+
+	subnets := []*network.Subnet{}
+
+	for {
+		result := struct {
+			TotalCount int
+			VSwitches  struct {
+				VSwitch []*alibabaVSwitch
+			}
+		}{}
+
+		if err := p.request("DescribeVSwitches", payload, &result); err != nil {
+			return nil, err
+		}
+
+		for _, vsw := range result.VSwitches.VSwitch {
+			subnets = append(subnets, p.toSubnet(vsw))
+		}
+
+		if len(subnets) >= result.TotalCount || len(result.VSwitches.VSwitch) == 0 {
+			break
+		}
+
+		payload["PageNumber"] = payload["PageNumber"].(int) + 1
+	}
+
+	return subnets, nil
+
+}
+
+// Create a new subnet in a vpc
+func (p *AlibabaVpcDriver) CreateSubnet(item *network.Vpc, opts *network.SubnetCreateOpts) (*network.Subnet, error) {
+
+	payload := map[string]any{
+		"RegionId":    p.rq.RegionId,
+		"VpcId":       item.Id,
+		"ZoneId":      opts.Zone,
+		"CidrBlock":   opts.CidrBlock,
+		"VSwitchName": opts.Name,
+		"Description": opts.Description,
+	}
+
+	result := struct {
+		VSwitchId string
+	}{}
+
+	if err := p.request("CreateVSwitch", payload, &result); err != nil {
+		return nil, err
+	}
+
+	subnet := &network.Subnet{
+		Id:        result.VSwitchId,
+		Name:      opts.Name,
+		VpcId:     item.Id,
+		Zone:      opts.Zone,
+		CidrBlock: opts.CidrBlock,
+		State:     network.NetworkStatePENDING,
+		CreatedAt: time.Now(),
+	}
+
+	return subnet, nil
+
+}
+
+// Delete an existing subnet
+func (p *AlibabaVpcDriver) DeleteSubnet(subnet *network.Subnet) error {
+
+	payload := map[string]any{
+		"RegionId":  p.rq.RegionId,
+		"VSwitchId": subnet.Id,
+	}
+
+	return p.request("DeleteVSwitch", payload, nil)
+
+}
+
+// List all route tables in a vpc, with routes
+func (p *AlibabaVpcDriver) ListRouteTables(item *network.Vpc) ([]*network.RouteTable, error) {
+
+	payload := map[string]any{
+		"RegionId":   p.rq.RegionId,
+		"VpcId":      item.Id,
+		"PageNumber": 1,
+		"PageSize":   50,
+	}
+
+	tables := []*network.RouteTable{}
+
+	for {
+		result := struct {
+			TotalCount      int
+			RouterTableList struct {
+				RouterTableListType []*alibabaRouteTable
+			}
+		}{}
+
+		if err := p.request("DescribeRouteTableList", payload, &result); err != nil {
+			return nil, err
+		}
+
+		for _, table := range result.RouterTableList.RouterTableListType {
+			tables = append(tables, p.toRouteTable(table))
+		}
+
+		if len(tables) >= result.TotalCount || len(result.RouterTableList.RouterTableListType) == 0 {
+			break
+		}
+
+		payload["PageNumber"] = payload["PageNumber"].(int) + 1
+	}
+
+	// 路由条目需单独查询
+	for _, table := range tables {
+		routes, err := p.listRoutes(table.Id)
+		if err != nil {
+			return nil, err
+		}
+		table.Routes = routes
+	}
+
+	return tables, nil
+
+}
+
+// Create a new route table in a vpc
+func (p *AlibabaVpcDriver) CreateRouteTable(item *network.Vpc, name string) (*network.RouteTable, error) {
+
+	payload := map[string]any{
+		"RegionId":       p.rq.RegionId,
+		"VpcId":          item.Id,
+		"RouteTableName": name,
+	}
+
+	result := struct {
+		RouteTableId string
+	}{}
+
+	if err := p.request("CreateRouteTable", payload, &result); err != nil {
+		return nil, err
+	}
+
+	table := &network.RouteTable{
+		Id:        result.RouteTableId,
+		Name:      name,
+		VpcId:     item.Id,
+		SubnetIds: []string{},
+		Routes:    []*network.Route{},
+		CreatedAt: time.Now(),
+	}
+
+	return table, nil
+
+}
+
+// Delete an existing route table
+func (p *AlibabaVpcDriver) DeleteRouteTable(table *network.RouteTable) error {
+
+	payload := map[string]any{
+		"RegionId":     p.rq.RegionId,
+		"RouteTableId": table.Id,
+	}
+
+	return p.request("DeleteRouteTable", payload, nil)
+
+}
+
+// Bind a subnet to route table
+func (p *AlibabaVpcDriver) AssociateRouteTable(table *network.RouteTable, subnet *network.Subnet) error {
+
+	payload := map[string]any{
+		"RegionId":     p.rq.RegionId,
+		"RouteTableId": table.Id,
+		"VSwitchId":    subnet.Id,
+	}
+
+	err := p.request("AssociateRouteTable", payload, nil)
+
+	if err == nil {
+		subnet.RouteTableId = table.Id
+	}
+
+	return err
+
+}
+
+// Add a route to route table
+func (p *AlibabaVpcDriver) CreateRoute(table *network.RouteTable, route *network.Route) (*network.Route, error) {
+
+	nextHopType, ok := alibabaNextHopTypes[route.NextHopType]
+	if !ok || route.NextHopType == network.NextHopLOCAL {
+		return nil, network.NotSupportedError
+	}
+
+	payload := map[string]any{
+		"RegionId":             p.rq.RegionId,
+		"RouteTableId":         table.Id,
+		"DestinationCidrBlock": route.Destination,
+		"NextHopType":          nextHopType,
+		"NextHopId":            route.NextHopId,
+		"Description":          route.Description,
+	}
+
+	result := struct {
+		RouteEntryId string
+	}{}
+
+	if err := p.request("CreateRouteEntry", payload, &result); err != nil {
+		return nil, err
+	}
+
+	created := *route
+	created.Id = result.RouteEntryId
+
+	return &created, nil
+
+}
+
+// Remove a route from route table
+func (p *AlibabaVpcDriver) DeleteRoute(table *network.RouteTable, route *network.Route) error {
+
+	payload := map[string]any{
+		"RegionId":     p.rq.RegionId,
+		"RouteTableId": table.Id,
+		"RouteEntryId": route.Id,
+	}
+
+	return p.request("DeleteRouteEntry", payload, nil)
+
+}
+
+// 查询路由条目
+func (p *AlibabaVpcDriver) listRoutes(tableId string) ([]*network.Route, error) {
+
+	payload := map[string]any{
+		"RegionId":     p.rq.RegionId,
+		"RouteTableId": tableId,
+		"MaxResult":    100,
+	}
+
+	routes := []*network.Route{}
+
+	for {
+		result := struct {
+			NextToken   string
+			RouteEntrys struct {
+				RouteEntry []*alibabaRouteEntry
+			}
+		}{}
+
+		if err := p.request("DescribeRouteEntryList", payload, &result); err != nil {
+			return nil, err
+		}
+
+		for _, entry := range result.RouteEntrys.RouteEntry {
+			routes = append(routes, p.toRoute(entry))
+		}
+
+		if result.NextToken == "" {
+			break
+		}
+
+		payload["NextToken"] = result.NextToken
+	}
+
+	return routes, nil
+
+}
+
+// 调用专有网络接口
+func (p *AlibabaVpcDriver) request(action string, payload, result any) error {
+
+	return p.client.Request("vpc", "2016-04-28", action, payload, result)
+
+}
+
+// 转换专有网络信息
+func (p *AlibabaVpcDriver) toVpc(item *alibabaVpc) *network.Vpc {
+
+	created, _ := time.Parse(time.RFC3339, item.CreationTime)
+
+	vpc := &network.Vpc{
+		Id:            item.VpcId,
+		Name:          item.VpcName,
+		CidrBlock:     item.CidrBlock,
+		Ipv6CidrBlock: item.Ipv6CidrBlock,
+		State:         network.NetworkStateUNKNOWN,
+		IsDefault:     item.IsDefault,
+		CreatedAt:     created,
+		Extra: map[string]interface{}{
+			"VRouterId":   item.VRouterId,
+			"Description": item.Description,
+		},
+	}
+
+	if state, ok := alibabaNetworkStates[item.Status]; ok {
+		vpc.State = state
+	}
+
+	return vpc
+
+}
+
+// 转换交换机信息
+func (p *AlibabaVpcDriver) toSubnet(item *alibabaVSwitch) *network.Subnet {
+
+	created, _ := time.Parse(time.RFC3339, item.CreationTime)
+
+	subnet := &network.Subnet{
+		Id:           item.VSwitchId,
+		Name:         item.VSwitchName,
+		VpcId:        item.VpcId,
+		Zone:         item.ZoneId,
+		CidrBlock:    item.CidrBlock,
+		AvailableIps: item.AvailableIpAddressCount,
+		RouteTableId: item.RouteTable.RouteTableId,
+		State:        network.NetworkStateUNKNOWN,
+		IsDefault:    item.IsDefault,
+		CreatedAt:    created,
+		Extra: map[string]interface{}{
+			"Description": item.Description,
+		},
+	}
+
+	if state, ok := alibabaNetworkStates[item.Status]; ok {
+		subnet.State = state
+	}
+
+	return subnet
+
+}
+
+// 转换路由表信息
+func (p *AlibabaVpcDriver) toRouteTable(item *alibabaRouteTable) *network.RouteTable {
+
+	created, _ := time.Parse(time.RFC3339, item.CreationTime)
+
+	return &network.RouteTable{
+		Id:        item.RouteTableId,
+		Name:      item.RouteTableName,
+		VpcId:     item.VpcId,
+		IsDefault: item.RouteTableType == "System",
+		SubnetIds: append([]string{}, item.VSwitchIds.VSwitchId...),
+		Routes:    []*network.Route{},
+		CreatedAt: created,
+		Extra: map[string]interface{}{
+			"RouteTableType": item.RouteTableType,
+		},
+	}
+
+}
+
+// 转换路由条目
+func (p *AlibabaVpcDriver) toRoute(item *alibabaRouteEntry) *network.Route {
+
+	route := &network.Route{
+		Id:          item.RouteEntryId,
+		Destination: item.DestinationCidrBlock,
+		NextHopType: network.NextHopUNKNOWN,
+		Description: item.Description,
+		Extra: map[string]interface{}{
+			"Type":   item.Type,
+			"Status": item.Status,
+		},
+	}
+
+	if len(item.NextHops.NextHop) > 0 {
+		hop := item.NextHops.NextHop[0]
+		route.NextHopId = hop.NextHopId
+		for k, v := range alibabaNextHopTypes {
+			if v == hop.NextHopType {
+				route.NextHopType = k
+			}
+		}
+	}
+
+	return route
+
+}
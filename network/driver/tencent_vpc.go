@@ -0,0 +1,430 @@
+package drivers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/rehiy/cloudgo/network"
+	"github.com/rehiy/cloudgo/provider"
+	"github.com/rehiy/cloudgo/provider/tencent"
+
+	tc "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	vpc "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/vpc/v20170312"
+)
+
+// 下一跳类型映射，没有弹性网卡类型，CVM 类型为公网网关
+
+var tencentNextHopTypes = map[network.NextHopType]string{
+	network.NextHopLOCAL:    "LOCAL",
+	network.NextHopINSTANCE: "NORMAL_CVM",
+	network.NextHopHAVIP:    "HAVIP",
+	network.NextHopNAT:      "NAT",
+	network.NextHopVPN:      "VPN",
+	network.NextHopPEERING:  "PEERCONNECTION",
+}
+
+type TencentVpcDriver struct {
+	client *tencent.Client
+	vpc    *vpc.Client
+	rq     *provider.ReqeustParam
+}
+
+func NewTencentVpcDriver(rq *provider.ReqeustParam) *TencentVpcDriver {
+
+	client := tencent.NewClient(rq)
+	vc, _ := client.Vpc()
+
+	return &TencentVpcDriver{client, vc, rq}
+
+}
+
+// List all vpcs
+func (p *TencentVpcDriver) ListVpcs() ([]*network.Vpc, error) {
+
+	request := vpc.NewDescribeVpcsRequest()
+	request.Limit = tc.StringPtr("100")
+
+	vpcs := []*network.Vpc{}
+
+	for {
+		request.Offset = tc.StringPtr(strconv.Itoa(len(vpcs)))
+
+		resp, err := p.vpc.DescribeVpcs(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range resp.Response.VpcSet {
+			vpcs = append(vpcs, p.toVpc(item))
+		}
+
+		if len(vpcs) >= int(*resp.Response.TotalCount) || len(resp.Response.VpcSet) == 0 {
+			break
+		}
+	}
+
+	return vpcs, nil
+
+}
+
+// Create a new vpc, description is not supported
+func (p *TencentVpcDriver) CreateVpc(opts *network.VpcCreateOpts) (*network.Vpc, error) {
+
+	if opts.Description != "" {
+		return nil, network.NotSupportedError
+	}
+
+	request := vpc.NewCreateVpcRequest()
+	request.VpcName = tc.StringPtr(opts.Name)
+	request.CidrBlock = tc.StringPtr(opts.CidrBlock)
+
+	resp, err := p.vpc.CreateVpc(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p.toVpc(resp.Response.Vpc), nil
+
+}
+
+// Delete an existing vpc
+func (p *TencentVpcDriver) DeleteVpc(item *network.Vpc) error {
+
+	request := vpc.NewDeleteVpcRequest()
+	request.VpcId = tc.StringPtr(item.Id)
+
+	_, err := p.vpc.DeleteVpc(request)
+
+	return err
+
+}
+
+// List all subnets in a vpc
+func (p *TencentVpcDriver) ListSubnets(item *network.Vpc) ([]*network.Subnet, error) {
+
+	request := vpc.NewDescribeSubnetsRequest()
+	request.Limit = tc.StringPtr("100")
+	request.Filters = []*vpc.Filter{
+		{Name: tc.StringPtr("vpc-id"), Values: []*string{tc.StringPtr(item.Id)}},
+	}
+
+	subnets := []*network.Subnet{}
+
+	for {
+		request.Offset = tc.StringPtr(strconv.Itoa(len(subnets)))
+
+		resp, err := p.vpc.DescribeSubnets(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, subnet := range resp.Response.SubnetSet {
+			subnets = append(subnets, p.toSubnet(subnet))
+		}
+
+		if len(subnets) >= int(*resp.Response.TotalCount) || len(resp.Response.SubnetSet) == 0 {
+			break
+		}
+	}
+
+	return subnets, nil
+
+}
+
+// Create a new subnet in a vpc, description is not supported
+func (p *TencentVpcDriver) CreateSubnet(item *network.Vpc, opts *network.SubnetCreateOpts) (*network.Subnet, error) {
+
+	if opts.Description != "" {
+		return nil, network.NotSupportedError
+	}
+
+	request := vpc.NewCreateSubnetRequest()
+	request.VpcId = tc.StringPtr(item.Id)
+	request.SubnetName = tc.StringPtr(opts.Name)
+	request.CidrBlock = tc.StringPtr(opts.CidrBlock)
+	request.Zone = tc.StringPtr(opts.Zone)
+
+	resp, err := p.vpc.CreateSubnet(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p.toSubnet(resp.Response.Subnet), nil
+
+}
+
+// Delete an existing subnet
+func (p *TencentVpcDriver) DeleteSubnet(subnet *network.Subnet) error {
+
+	request := vpc.NewDeleteSubnetRequest()
+	request.SubnetId = tc.StringPtr(subnet.Id)
+
+	_, err := p.vpc.DeleteSubnet(request)
+
+	return err
+
+}
+
+// List all route tables in a vpc, with routes
+func (p *TencentVpcDriver) ListRouteTables(item *network.Vpc) ([]*network.RouteTable, error) {
+
+	request := vpc.NewDescribeRouteTablesRequest()
+	request.Limit = tc.StringPtr("100")
+	request.Filters = []*vpc.Filter{
+		{Name: tc.StringPtr("vpc-id"), Values: []*string{tc.StringPtr(item.Id)}},
+	}
+
+	tables := []*network.RouteTable{}
+
+	for {
+		request.Offset = tc.StringPtr(strconv.Itoa(len(tables)))
+
+		resp, err := p.vpc.DescribeRouteTables(request)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, table := range resp.Response.RouteTableSet {
+			tables = append(tables, p.toRouteTable(table))
+		}
+
+		if len(tables) >= int(*resp.Response.TotalCount) || len(resp.Response.RouteTableSet) == 0 {
+			break
+		}
+	}
+
+	return tables, nil
+
+}
+
+// Create a new route table in a vpc
+func (p *TencentVpcDriver) CreateRouteTable(item *network.Vpc, name string) (*network.RouteTable, error) {
+
+	request := vpc.NewCreateRouteTableRequest()
+	request.VpcId = tc.StringPtr(item.Id)
+	request.RouteTableName = tc.StringPtr(name)
+
+	resp, err := p.vpc.CreateRouteTable(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return p.toRouteTable(resp.Response.RouteTable), nil
+
+}
+
+// Delete an existing route table
+func (p *TencentVpcDriver) DeleteRouteTable(table *network.RouteTable) error {
+
+	request := vpc.NewDeleteRouteTableRequest()
+	request.RouteTableId = tc.StringPtr(table.Id)
+
+	_, err := p.vpc.DeleteRouteTable(request)
+
+	return err
+
+}
+
+// Bind a subnet to route table
+func (p *TencentVpcDriver) AssociateRouteTable(table *network.RouteTable, subnet *network.Subnet) error {
+
+	request := vpc.NewReplaceRouteTableAssociationRequest()
+	request.RouteTableId = tc.StringPtr(table.Id)
+	request.SubnetId = tc.StringPtr(subnet.Id)
+
+	_, err := p.vpc.ReplaceRouteTableAssociation(request)
+
+	if err == nil {
+		subnet.RouteTableId = table.Id
+	}
+
+	return err
+
+}
+
+// Add a route to route table
+func (p *TencentVpcDriver) CreateRoute(table *network.RouteTable, route *network.Route) (*network.Route, error) {
+
+	gatewayType, ok := tencentNextHopTypes[route.NextHopType]
+	if !ok {
+		return nil, network.NotSupportedError
+	}
+
+	request := vpc.NewCreateRoutesRequest()
+	request.RouteTableId = tc.StringPtr(table.Id)
+	request.Routes = []*vpc.Route{
+		{
+			DestinationCidrBlock: tc.StringPtr(route.Destination),
+			GatewayType:          tc.StringPtr(gatewayType),
+			GatewayId:            tc.StringPtr(route.NextHopId),
+			RouteDescription:     tc.StringPtr(route.Description),
+		},
+	}
+
+	resp, err := p.vpc.CreateRoutes(request)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// 返回更新后的路由表，按目标网段查找新路由
+	for _, table := range resp.Response.RouteTableSet {
+		for _, item := range table.RouteSet {
+			if p.stringValue(item.DestinationCidrBlock) == route.Destination && p.stringValue(item.GatewayId) == route.NextHopId {
+				return p.toRoute(item), nil
+			}
+		}
+	}
+
+	return route, nil
+
+}
+
+// Remove a route from route table
+func (p *TencentVpcDriver) DeleteRoute(table *network.RouteTable, route *network.Route) error {
+
+	request := vpc.NewDeleteRoutesRequest()
+	request.RouteTableId = tc.StringPtr(table.Id)
+	request.Routes = []*vpc.Route{
+		{RouteItemId: tc.StringPtr(route.Id)},
+	}
+
+	_, err := p.vpc.DeleteRoutes(request)
+
+	return err
+
+}
+
+// 转换私有网络信息
+func (p *TencentVpcDriver) toVpc(item *vpc.Vpc) *network.Vpc {
+
+	return &network.Vpc{
+		Id:            p.stringValue(item.VpcId),
+		Name:          p.stringValue(item.VpcName),
+		CidrBlock:     p.stringValue(item.CidrBlock),
+		Ipv6CidrBlock: p.stringValue(item.Ipv6CidrBlock),
+		State:         network.NetworkStateAVAILABLE,
+		IsDefault:     p.boolValue(item.IsDefault),
+		CreatedAt:     p.timeValue(item.CreatedTime),
+		Extra: map[string]interface{}{
+			"DnsServerSet": tc.StringValues(item.DnsServerSet),
+		},
+	}
+
+}
+
+// 转换子网信息
+func (p *TencentVpcDriver) toSubnet(item *vpc.Subnet) *network.Subnet {
+
+	subnet := &network.Subnet{
+		Id:           p.stringValue(item.SubnetId),
+		Name:         p.stringValue(item.SubnetName),
+		VpcId:        p.stringValue(item.VpcId),
+		Zone:         p.stringValue(item.Zone),
+		CidrBlock:    p.stringValue(item.CidrBlock),
+		RouteTableId: p.stringValue(item.RouteTableId),
+		State:        network.NetworkStateAVAILABLE,
+		IsDefault:    p.boolValue(item.IsDefault),
+		CreatedAt:    p.timeValue(item.CreatedTime),
+		Extra: map[string]interface{}{
+			"NetworkAclId": p.stringValue(item.NetworkAclId),
+		},
+	}
+
+	if item.AvailableIpAddressCount != nil {
+		subnet.AvailableIps = int(*item.AvailableIpAddressCount)
+	}
+
+	return subnet
+
+}
+
+// 转换路由表信息
+func (p *TencentVpcDriver) toRouteTable(item *vpc.RouteTable) *network.RouteTable {
+
+	table := &network.RouteTable{
+		Id:        p.stringValue(item.RouteTableId),
+		Name:      p.stringValue(item.RouteTableName),
+		VpcId:     p.stringValue(item.VpcId),
+		IsDefault: p.boolValue(item.Main),
+		SubnetIds: []string{},
+		Routes:    []*network.Route{},
+		CreatedAt: p.timeValue(item.CreatedTime),
+	}
+
+	for _, assoc := range item.AssociationSet {
+		table.SubnetIds = append(table.SubnetIds, p.stringValue(assoc.SubnetId))
+	}
+
+	for _, route := range item.RouteSet {
+		table.Routes = append(table.Routes, p.toRoute(route))
+	}
+
+	return table
+
+}
+
+// 转换路由条目
+func (p *TencentVpcDriver) toRoute(item *vpc.Route) *network.Route {
+
+	gatewayType := p.stringValue(item.GatewayType)
+
+	route := &network.Route{
+		Id:          p.stringValue(item.RouteItemId),
+		Destination: p.stringValue(item.DestinationCidrBlock),
+		NextHopType: network.NextHopUNKNOWN,
+		NextHopId:   p.stringValue(item.GatewayId),
+		Description: p.stringValue(item.RouteDescription),
+		Extra: map[string]interface{}{
+			"GatewayType": gatewayType,
+			"RouteType":   p.stringValue(item.RouteType),
+			"Enabled":     p.boolValue(item.Enabled),
+		},
+	}
+
+	for k, v := range tencentNextHopTypes {
+		if v == gatewayType {
+			route.NextHopType = k
+		}
+	}
+
+	return route
+
+}
+
+// 读取可能为空的字符串
+func (p *TencentVpcDriver) stringValue(v *string) string {
+
+	if v == nil {
+		return ""
+	}
+
+	return *v
+
+}
+
+// 读取可能为空的布尔值
+func (p *TencentVpcDriver) boolValue(v *bool) bool {
+
+	return v != nil && *v
+
+}
+
+// 读取可能为空的时间，格式为 2006-01-02 15:04:05
+func (p *TencentVpcDriver) timeValue(v *string) time.Time {
+
+	if v == nil {
+		return time.Time{}
+	}
+
+	t, _ := time.ParseInLocation("2006-01-02 15:04:05", *v, time.Local)
+
+	return t
+
+}
@@ -0,0 +1,114 @@
+package network
+
+import (
+	"time"
+)
+
+// Define a Network Provider interface for VPC resources
+
+type NetworkProvider interface {
+
+	// List all vpcs
+	ListVpcs() ([]*Vpc, error)
+
+	// Create a new vpc
+	CreateVpc(opts *VpcCreateOpts) (*Vpc, error)
+
+	// Delete an existing vpc
+	DeleteVpc(vpc *Vpc) error
+
+	// List all subnets in a vpc
+	ListSubnets(vpc *Vpc) ([]*Subnet, error)
+
+	// Create a new subnet in a vpc
+	CreateSubnet(vpc *Vpc, opts *SubnetCreateOpts) (*Subnet, error)
+
+	// Delete an existing subnet
+	DeleteSubnet(subnet *Subnet) error
+
+	// List all route tables in a vpc, with routes
+	ListRouteTables(vpc *Vpc) ([]*RouteTable, error)
+
+	// Create a new route table in a vpc
+	CreateRouteTable(vpc *Vpc, name string) (*RouteTable, error)
+
+	// Delete an existing route table
+	DeleteRouteTable(table *RouteTable) error
+
+	// Bind a subnet to route table
+	AssociateRouteTable(table *RouteTable, subnet *Subnet) error
+
+	// Add a route to route table
+	CreateRoute(table *RouteTable, route *Route) (*Route, error)
+
+	// Remove a route from route table
+	DeleteRoute(table *RouteTable, route *Route) error
+}
+
+// Vpc represents a private network
+
+type Vpc struct {
+	Id            string
+	Name          string
+	CidrBlock     string
+	Ipv6CidrBlock string
+	State         NetworkState
+	IsDefault     bool
+	CreatedAt     time.Time
+	Extra         map[string]interface{}
+}
+
+type VpcCreateOpts struct {
+	Name        string
+	CidrBlock   string
+	Description string
+	Extra       map[string]interface{}
+}
+
+// Subnet represents a subnet (vSwitch) in a vpc
+
+type Subnet struct {
+	Id           string
+	Name         string
+	VpcId        string
+	Zone         string
+	CidrBlock    string
+	AvailableIps int
+	RouteTableId string
+	State        NetworkState
+	IsDefault    bool
+	CreatedAt    time.Time
+	Extra        map[string]interface{}
+}
+
+type SubnetCreateOpts struct {
+	Name        string
+	Zone        string
+	CidrBlock   string
+	Description string
+	Extra       map[string]interface{}
+}
+
+// RouteTable represents a route table in a vpc
+
+type RouteTable struct {
+	Id        string
+	Name      string
+	VpcId     string
+	IsDefault bool
+	SubnetIds []string
+	Routes    []*Route
+	CreatedAt time.Time
+	Extra     map[string]interface{}
+}
+
+// Route represents an entry of route table, NextHopId is the vendor resource id
+
+type Route struct {
+	Id          string
+	Destination string
+	NextHopType NextHopType
+	NextHopId   string
+	Description string
+	Extra       map[string]interface{}
+}
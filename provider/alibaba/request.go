@@ -0,0 +1,66 @@
+package alibaba
+
+import (
+	"encoding/json"
+
+	ac "github.com/alibabacloud-go/darabonba-openapi/v2/client"
+	ou "github.com/alibabacloud-go/openapi-util/service"
+	tea "github.com/alibabacloud-go/tea/tea"
+)
+
+// 通用请求，用于调用未引入 SDK 的产品接口
+
+func (c *Client) Request(product, version, action string, payload, result any) error {
+
+	// 按产品名称推导接口域名
+	endpoint := product + "." + c.RegionId + ".aliyuncs.com"
+
+	return c.RequestEndpoint(endpoint, version, action, payload, result)
+
+}
+
+// 指定域名的通用请求，用于调用不分地域的接口
+
+func (c *Client) RequestEndpoint(endpoint, version, action string, payload, result any) error {
+
+	config := *c.config
+	config.Endpoint = tea.String(endpoint)
+
+	client, err := ac.NewClient(&config)
+	if err != nil {
+		return err
+	}
+
+	params := &ac.Params{
+		Action:      tea.String(action),
+		Version:     tea.String(version),
+		Protocol:    tea.String("HTTPS"),
+		Pathname:    tea.String("/"),
+		Method:      tea.String("POST"),
+		AuthType:    tea.String("AK"),
+		Style:       tea.String("RPC"),
+		ReqBodyType: tea.String("formData"),
+		BodyType:    tea.String("json"),
+	}
+
+	request := &ac.OpenApiRequest{
+		Query: ou.Query(payload),
+	}
+
+	resp, err := client.CallApi(params, request, c.runtime)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(resp["body"])
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, result)
+
+}
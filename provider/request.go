@@ -6,6 +6,7 @@ type ReqeustParam struct {
 	SecretId  string `note:"访问密钥 Id"`
 	SecretKey string `note:"访问密钥 Key"`
 	RegionId  string `note:"资源所在区域"`
+	AccountId string `note:"账号 Id，Cloudflare 创建区域时必填"`
 	Endpoint  string `note:"指定接口域名"`
 	Service   string `note:"产品名称"`
 	Version   string `note:"接口版本"`
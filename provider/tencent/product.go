@@ -5,6 +5,7 @@ import (
 	cvm "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/cvm/v20170312"
 	dnspod "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/dnspod/v20210323"
 	lighthouse "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/lighthouse/v20200324"
+	vpc "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/vpc/v20170312"
 )
 
 func (c *Client) Cbs() (client *cbs.Client, err error) {
@@ -30,3 +31,9 @@ func (c *Client) Lighthouse() (client *lighthouse.Client, err error) {
 	return lighthouse.NewClient(c.credential, c.RegionId, c.profile)
 
 }
+
+func (c *Client) Vpc() (client *vpc.Client, err error) {
+
+	return vpc.NewClient(c.credential, c.RegionId, c.profile)
+
+}
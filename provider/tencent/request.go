@@ -0,0 +1,55 @@
+package tencent
+
+import (
+	"encoding/json"
+
+	tc "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common"
+	th "github.com/tencentcloud/tencentcloud-sdk-go/tencentcloud/common/http"
+)
+
+// 通用请求，用于调用未引入 SDK 的产品接口
+
+func (c *Client) Request(service, version, action string, payload, result any) error {
+
+	// 按产品名称推导接口域名
+	profile := *c.profile
+	httpProfile := *c.profile.HttpProfile
+	httpProfile.Endpoint = ""
+	profile.HttpProfile = &httpProfile
+
+	client := tc.NewCommonClient(c.credential, c.RegionId, &profile)
+
+	request := th.NewCommonRequest(service, version, action)
+
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		if err := request.SetActionParameters(data); err != nil {
+			return err
+		}
+	}
+
+	response := th.NewCommonResponse()
+
+	if err := client.Send(request, response); err != nil {
+		return err
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	// 解析 Response 节点
+	body := struct {
+		Response json.RawMessage
+	}{}
+
+	if err := json.Unmarshal(response.GetBody(), &body); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body.Response, result)
+
+}
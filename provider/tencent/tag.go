@@ -0,0 +1,117 @@
+package tencent
+
+// 标签接口 https://cloud.tencent.com/document/api/651
+
+type TagItem struct {
+	TagKey   string
+	TagValue string
+}
+
+// 生成资源六段式名称
+
+func (c *Client) ResourceNames(service, prefix string, ids []string) ([]string, error) {
+
+	result := struct {
+		OwnerUin string
+	}{}
+
+	err := c.Request("cam", "2019-01-16", "GetUserAppId", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, id := range ids {
+		names = append(names, "qcs::"+service+":"+c.RegionId+":uin/"+result.OwnerUin+":"+prefix+"/"+id)
+	}
+
+	return names, nil
+
+}
+
+// 为资源绑定标签
+
+func (c *Client) TagResources(resources []string, tags map[string]string) error {
+
+	items := []TagItem{}
+	for k, v := range tags {
+		items = append(items, TagItem{k, v})
+	}
+
+	payload := map[string]any{
+		"ResourceList": resources,
+		"Tags":         items,
+	}
+
+	return c.Request("tag", "2018-08-13", "TagResources", payload, nil)
+
+}
+
+// 为资源解绑标签
+
+func (c *Client) UntagResources(resources []string, keys []string) error {
+
+	payload := map[string]any{
+		"ResourceList": resources,
+		"TagKeys":      keys,
+	}
+
+	return c.Request("tag", "2018-08-13", "UnTagResources", payload, nil)
+
+}
+
+// 查询资源标签，返回资源 Id 到标签的映射
+
+func (c *Client) ResourceTags(service, prefix string, ids []string) (map[string]map[string]string, error) {
+
+	tags := map[string]map[string]string{}
+	for _, id := range ids {
+		tags[id] = map[string]string{}
+	}
+
+	// 每次最多查询 50 个资源
+	for start := 0; start < len(ids); start += 50 {
+		end := start + 50
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		for offset := 0; ; offset += 100 {
+			result := struct {
+				TotalCount int
+				Tags       []struct {
+					ResourceId string
+					TagKey     string
+					TagValue   string
+				}
+			}{}
+
+			payload := map[string]any{
+				"ServiceType":    service,
+				"ResourcePrefix": prefix,
+				"ResourceRegion": c.RegionId,
+				"ResourceIds":    ids[start:end],
+				"Offset":         offset,
+				"Limit":          100,
+			}
+
+			err := c.Request("tag", "2018-08-13", "DescribeResourceTagsByResourceIds", payload, &result)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, tag := range result.Tags {
+				if tags[tag.ResourceId] != nil {
+					tags[tag.ResourceId][tag.TagKey] = tag.TagValue
+				}
+			}
+
+			if len(result.Tags) < 100 || offset+100 >= result.TotalCount {
+				break
+			}
+		}
+	}
+
+	return tags, nil
+
+}